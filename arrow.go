@@ -0,0 +1,803 @@
+package plygo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+)
+
+// ArrowType is the logical type of an Arrow column. Columns use one
+// canonical physical layout per type: 64-bit integers and floats, UTF-8
+// strings with 32-bit offsets, bit-packed booleans, microsecond UTC
+// timestamps and nanosecond durations.
+type ArrowType int
+
+const (
+	ArrowInt64 ArrowType = iota + 1
+	ArrowFloat64
+	ArrowBool
+	ArrowString
+	ArrowTimestamp
+	ArrowDuration
+)
+
+func (t ArrowType) String() string {
+	switch t {
+	case ArrowInt64:
+		return "int64"
+	case ArrowFloat64:
+		return "float64"
+	case ArrowBool:
+		return "bool"
+	case ArrowString:
+		return "utf8"
+	case ArrowTimestamp:
+		return "timestamp[us, UTC]"
+	case ArrowDuration:
+		return "duration[ns]"
+	}
+	return fmt.Sprintf("ArrowType(%d)", int(t))
+}
+
+type ArrowField struct {
+	Name     string
+	Type     ArrowType
+	Nullable bool
+}
+
+// ArrowColumn holds one column of a record batch in Arrow's memory layout.
+// Validity has bit i (least significant first) set when row i is not null,
+// and is nil when the column has no nulls. Offsets is only used by string
+// columns; Data holds little-endian values.
+type ArrowColumn struct {
+	Field     ArrowField
+	Length    int
+	NullCount int
+	Validity  []byte
+	Offsets   []int32
+	Data      []byte
+}
+
+// RecordBatch is a set of equal-length columns.
+type RecordBatch struct {
+	Length  int
+	Columns []*ArrowColumn
+}
+
+// Fields returns the schema of the batch.
+func (b *RecordBatch) Fields() []ArrowField {
+	fields := make([]ArrowField, len(b.Columns))
+	for i, col := range b.Columns {
+		fields[i] = col.Field
+	}
+	return fields
+}
+
+func (c *ArrowColumn) IsNull(i int) bool {
+	return c.Validity != nil && c.Validity[i/8]&(1<<(i%8)) == 0
+}
+
+// Value returns row i as int64, float64, bool, string, time.Time,
+// time.Duration or nil.
+func (c *ArrowColumn) Value(i int) any {
+	if c.IsNull(i) {
+		return nil
+	}
+	switch c.Field.Type {
+	case ArrowInt64:
+		return int64(binary.LittleEndian.Uint64(c.Data[8*i:]))
+	case ArrowFloat64:
+		return math.Float64frombits(binary.LittleEndian.Uint64(c.Data[8*i:]))
+	case ArrowBool:
+		return c.Data[i/8]&(1<<(i%8)) != 0
+	case ArrowString:
+		return string(c.Data[c.Offsets[i]:c.Offsets[i+1]])
+	case ArrowTimestamp:
+		return time.UnixMicro(int64(binary.LittleEndian.Uint64(c.Data[8*i:]))).UTC()
+	case ArrowDuration:
+		return time.Duration(binary.LittleEndian.Uint64(c.Data[8*i:]))
+	}
+	return nil
+}
+
+// RecordBatches converts the pipeline to Arrow record batches of at most
+// size rows (all rows in one batch when size <= 0). Columns follow the
+// WriteCSV order and names. Integers become int64, pointer fields are
+// nullable, and types without an Arrow counterpart are written as strings.
+func (p *Pipeline[T]) RecordBatches(size int) []*RecordBatch {
+	fields, names := p.arrowFields()
+	if size <= 0 {
+		size = max(len(p.data), 1)
+	}
+
+	batches := make([]*RecordBatch, 0, len(p.data)/size+1)
+	for start := 0; start < len(p.data); start += size {
+		end := min(start+size, len(p.data))
+		batch := &RecordBatch{Length: end - start, Columns: make([]*ArrowColumn, len(fields))}
+		for j, field := range fields {
+			values := make([]any, end-start)
+			for i, item := range p.data[start:end] {
+				values[i] = getFieldValue(item, names[j])
+			}
+			batch.Columns[j] = buildArrowColumn(field, values)
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// FromRecordBatches converts record batches to a pipeline of structs, struct
+// pointers or map[string]any. Columns are matched to struct fields like
+// FromCSV headers; string columns are parsed into non-string fields.
+func FromRecordBatches[T any](batches ...*RecordBatch) (*Pipeline[T], error) {
+	var zero T
+	typ := reflect.TypeOf(zero)
+	structType, err := recordType(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]T, 0)
+	resultIdx := make([]int, 0)
+	var columns []string
+
+	for _, batch := range batches {
+		names := make([]string, len(batch.Columns))
+		for j, col := range batch.Columns {
+			names[j] = col.Field.Name
+			if col.Length < batch.Length {
+				return nil, fmt.Errorf("column %s has %d rows, batch has %d", col.Field.Name, col.Length, batch.Length)
+			}
+		}
+		if columns == nil {
+			columns = names
+		}
+
+		var targets []fieldTarget
+		if structType != nil {
+			targets = matchColumns(structType, names)
+		}
+
+		for i := 0; i < batch.Length; i++ {
+			var item T
+			if targets != nil {
+				v := recordValue(&item)
+				for _, t := range targets {
+					if err := setFromValue(v.Field(t.field), batch.Columns[t.column].Value(i)); err != nil {
+						return nil, fmt.Errorf("row %d: field %s: %w", len(result)+1, t.name, err)
+					}
+				}
+			} else if m, ok := any(&item).(*map[string]any); ok {
+				*m = make(map[string]any, len(names))
+				for j, name := range names {
+					(*m)[name] = batch.Columns[j].Value(i)
+				}
+			}
+			result = append(result, item)
+			resultIdx = append(resultIdx, len(result))
+		}
+	}
+
+	p := &Pipeline[T]{data: result, originalIndex: resultIdx}
+	if typ != nil && typ.Kind() == reflect.Map {
+		p.columns = columns
+	}
+	return p, nil
+}
+
+// WriteArrow writes the pipeline as an Arrow IPC file (the format read by
+// pyarrow.ipc.open_file and Feather v2), in batches of 64K rows.
+func (p *Pipeline[T]) WriteArrow(w io.Writer) error {
+	fields, _ := p.arrowFields()
+	return writeArrowFile(w, fields, p.RecordBatches(arrowBatchSize))
+}
+
+// FromArrow reads an Arrow IPC file into a pipeline.
+func FromArrow[T any](r io.Reader) (*Pipeline[T], error) {
+	batches, err := ReadArrow(r)
+	if err != nil {
+		return nil, err
+	}
+	return FromRecordBatches[T](batches...)
+}
+
+const arrowBatchSize = 64 * 1024
+
+func (p *Pipeline[T]) arrowFields() ([]ArrowField, []string) {
+	headers, names := p.exportColumns()
+	fields := make([]ArrowField, len(names))
+
+	var zero T
+	typ := reflect.TypeOf(zero)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ != nil && typ.Kind() == reflect.Struct {
+		for i, name := range names {
+			sf, _ := typ.FieldByName(name)
+			ft := sf.Type
+			nullable := false
+			switch ft.Kind() {
+			case reflect.Ptr:
+				ft, nullable = ft.Elem(), true
+			case reflect.Interface, reflect.Map, reflect.Slice:
+				nullable = true
+			}
+			fields[i] = ArrowField{Name: headers[i], Type: arrowTypeOf(ft), Nullable: nullable}
+		}
+		return fields, names
+	}
+
+	schema := inferSchema(names, p.data)
+	for i, col := range schema.Columns {
+		ft := col.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		fields[i] = ArrowField{Name: headers[i], Type: arrowTypeOf(ft), Nullable: true}
+	}
+	return fields, names
+}
+
+func arrowTypeOf(t reflect.Type) ArrowType {
+	switch {
+	case t == timeType:
+		return ArrowTimestamp
+	case t == durationType:
+		return ArrowDuration
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ArrowInt64
+	case reflect.Float32, reflect.Float64:
+		return ArrowFloat64
+	case reflect.Bool:
+		return ArrowBool
+	}
+	return ArrowString
+}
+
+func buildArrowColumn(field ArrowField, values []any) *ArrowColumn {
+	n := len(values)
+	col := &ArrowColumn{Field: field, Length: n}
+	validity := make([]byte, (n+7)/8)
+
+	switch field.Type {
+	case ArrowBool:
+		col.Data = make([]byte, (n+7)/8)
+	case ArrowString:
+		col.Offsets = make([]int32, n+1)
+	default:
+		col.Data = make([]byte, 8*n)
+	}
+
+	text := defaultCSVConfig()
+	for i, v := range values {
+		rv := reflect.ValueOf(v)
+		for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if isNil(v) || !rv.IsValid() {
+			col.NullCount++
+			if field.Type == ArrowString {
+				col.Offsets[i+1] = col.Offsets[i]
+			}
+			continue
+		}
+		validity[i/8] |= 1 << (i % 8)
+
+		switch field.Type {
+		case ArrowInt64:
+			binary.LittleEndian.PutUint64(col.Data[8*i:], uint64(arrowInt(rv)))
+		case ArrowFloat64:
+			binary.LittleEndian.PutUint64(col.Data[8*i:], math.Float64bits(toFloat64(rv.Interface())))
+		case ArrowBool:
+			if rv.Bool() {
+				col.Data[i/8] |= 1 << (i % 8)
+			}
+		case ArrowString:
+			s := formatCell(rv.Interface(), text)
+			col.Data = append(col.Data, s...)
+			col.Offsets[i+1] = int32(len(col.Data))
+		case ArrowTimestamp:
+			binary.LittleEndian.PutUint64(col.Data[8*i:], uint64(rv.Interface().(time.Time).UnixMicro()))
+		case ArrowDuration:
+			binary.LittleEndian.PutUint64(col.Data[8*i:], uint64(rv.Int()))
+		}
+	}
+
+	if col.NullCount > 0 {
+		col.Validity = validity
+	}
+	return col
+}
+
+func arrowInt(rv reflect.Value) int64 {
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float())
+	}
+	return rv.Int()
+}
+
+// setFromValue stores val in dst, allocating pointers and parsing strings
+// into non-string fields. A nil val leaves dst unchanged.
+func setFromValue(dst reflect.Value, val any) error {
+	if val == nil {
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr {
+		elem := reflect.New(dst.Type().Elem())
+		if err := setFromValue(elem.Elem(), val); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	switch v := val.(type) {
+	case []byte:
+		if dst.Kind() == reflect.String {
+			dst.SetString(string(v))
+			return nil
+		}
+	case string:
+		if dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes([]byte(v))
+			return nil
+		}
+	}
+	if s, ok := val.(string); ok && dst.Kind() != reflect.String {
+		return parseInto(dst, s, defaultCSVConfig().timeLayouts)
+	}
+	if !assignValue(dst, reflect.ValueOf(val)) {
+		return fmt.Errorf("cannot assign %T to %s", val, dst.Type())
+	}
+	return nil
+}
+
+// Arrow IPC constants from Schema.fbs and Message.fbs.
+const (
+	arrowMetadataV5 = 4
+
+	arrowHeaderSchema      = 1
+	arrowHeaderDictionary  = 2
+	arrowHeaderRecordBatch = 3
+
+	arrowTypeInt           = 2
+	arrowTypeFloatingPoint = 3
+	arrowTypeUtf8          = 5
+	arrowTypeBool          = 6
+	arrowTypeDate          = 8
+	arrowTypeTimestamp     = 10
+	arrowTypeDuration      = 18
+	arrowTypeLargeUtf8     = 20
+)
+
+var arrowMagic = []byte("ARROW1")
+
+const errArrow = formatError("malformed Arrow record batch")
+
+// countingWriter tracks the file position for the footer's block offsets.
+type countingWriter struct {
+	w   io.Writer
+	pos int64
+	err error
+}
+
+func (c *countingWriter) write(b []byte) {
+	if c.err != nil {
+		return
+	}
+	n, err := c.w.Write(b)
+	c.pos += int64(n)
+	c.err = err
+}
+
+func writeArrowFile(w io.Writer, fields []ArrowField, batches []*RecordBatch) error {
+	cw := &countingWriter{w: w}
+	cw.write(arrowMagic)
+	cw.write([]byte{0, 0})
+
+	writeArrowMessage(cw, arrowHeaderSchema, arrowSchemaTable(fields), nil)
+
+	blocks := make([]byte, 0, 24*len(batches))
+	for _, batch := range batches {
+		header, body := arrowBatchTable(batch)
+		offset := cw.pos
+		metaLen := writeArrowMessage(cw, arrowHeaderRecordBatch, header, body)
+
+		block := make([]byte, 24)
+		binary.LittleEndian.PutUint64(block, uint64(offset))
+		binary.LittleEndian.PutUint32(block[8:], uint32(metaLen))
+		binary.LittleEndian.PutUint64(block[16:], uint64(len(body)))
+		blocks = append(blocks, block...)
+	}
+
+	// End-of-stream marker, for readers that consume the file as a stream.
+	cw.write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+
+	footer := fbFinish(fbTable{
+		fbScalar(2, arrowMetadataV5),
+		fbChild(arrowSchemaTable(fields)),
+		fbChild(fbStructs{}),
+		fbChild(fbStructs{n: len(batches), data: blocks}),
+	})
+	cw.write(footer)
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(len(footer)))
+	cw.write(size)
+	cw.write(arrowMagic)
+	return cw.err
+}
+
+// writeArrowMessage writes an encapsulated message and returns the size of
+// its metadata including the 8-byte prefix.
+func writeArrowMessage(cw *countingWriter, headerType int, header fbTable, body []byte) int {
+	meta := fbFinish(fbTable{
+		fbScalar(2, arrowMetadataV5),
+		fbScalar(1, uint64(headerType)),
+		fbChild(header),
+		fbScalar(8, uint64(len(body))),
+	})
+
+	prefix := make([]byte, 8)
+	binary.LittleEndian.PutUint32(prefix, 0xffffffff)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(meta)))
+	cw.write(prefix)
+	cw.write(meta)
+	cw.write(body)
+	return 8 + len(meta)
+}
+
+func arrowSchemaTable(fields []ArrowField) fbTable {
+	vec := make(fbVector, len(fields))
+	for i, f := range fields {
+		var typeID int
+		var typ fbTable
+		switch f.Type {
+		case ArrowInt64:
+			typeID, typ = arrowTypeInt, fbTable{fbScalar(4, 64), fbBool(true)}
+		case ArrowFloat64:
+			typeID, typ = arrowTypeFloatingPoint, fbTable{fbScalar(2, 2)}
+		case ArrowBool:
+			typeID, typ = arrowTypeBool, fbTable{}
+		case ArrowString:
+			typeID, typ = arrowTypeUtf8, fbTable{}
+		case ArrowTimestamp:
+			typeID, typ = arrowTypeTimestamp, fbTable{fbScalar(2, 2), fbChild(fbString("UTC"))}
+		case ArrowDuration:
+			typeID, typ = arrowTypeDuration, fbTable{fbScalar(2, 3)}
+		}
+		vec[i] = fbTable{
+			fbChild(fbString(f.Name)),
+			fbBool(f.Nullable),
+			fbScalar(1, uint64(typeID)),
+			fbChild(typ),
+			nil,
+			fbChild(fbVector{}),
+		}
+	}
+	return fbTable{fbScalar(2, 0), fbChild(vec)}
+}
+
+func arrowBatchTable(batch *RecordBatch) (fbTable, []byte) {
+	var body bytes.Buffer
+	nodes := make([]byte, 0, 16*len(batch.Columns))
+	buffers := make([]byte, 0, 48*len(batch.Columns))
+
+	addBuffer := func(b []byte) {
+		entry := make([]byte, 16)
+		binary.LittleEndian.PutUint64(entry, uint64(body.Len()))
+		binary.LittleEndian.PutUint64(entry[8:], uint64(len(b)))
+		buffers = append(buffers, entry...)
+		body.Write(b)
+		for body.Len()%8 != 0 {
+			body.WriteByte(0)
+		}
+	}
+
+	for _, col := range batch.Columns {
+		node := make([]byte, 16)
+		binary.LittleEndian.PutUint64(node, uint64(col.Length))
+		binary.LittleEndian.PutUint64(node[8:], uint64(col.NullCount))
+		nodes = append(nodes, node...)
+
+		addBuffer(col.Validity)
+		if col.Field.Type == ArrowString {
+			offsets := make([]byte, 4*len(col.Offsets))
+			for i, off := range col.Offsets {
+				binary.LittleEndian.PutUint32(offsets[4*i:], uint32(off))
+			}
+			addBuffer(offsets)
+		}
+		addBuffer(col.Data)
+	}
+
+	return fbTable{
+		fbScalar(8, uint64(batch.Length)),
+		fbChild(fbStructs{n: len(batch.Columns), data: nodes}),
+		fbChild(fbStructs{n: len(buffers) / 16, data: buffers}),
+	}, body.Bytes()
+}
+
+// arrowSource describes a column as stored in a file, before conversion to
+// the canonical layout.
+type arrowSource struct {
+	field    ArrowField
+	typeID   int
+	bitWidth int
+	signed   bool
+	unit     int
+}
+
+// ReadArrow reads every record batch of an Arrow IPC file. Integer, float,
+// bool, UTF-8, date, timestamp and duration columns are supported and
+// converted to the canonical layouts described on ArrowType; dictionary
+// encoding, compression and nested types are not.
+func ReadArrow(r io.Reader) (batches []*RecordBatch, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 18 || !bytes.Equal(data[:6], arrowMagic) || !bytes.Equal(data[len(data)-6:], arrowMagic) {
+		return nil, errors.New("not an Arrow IPC file")
+	}
+	defer recoverFormat(&err)
+
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-10:]))
+	footer := fbRoot(fbBytes(data, len(data)-10-footerLen, footerLen))
+
+	schema, ok := footer.table(1)
+	if !ok {
+		return nil, errors.New("Arrow file has no schema")
+	}
+	sources, err := decodeArrowSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	if _, n := footer.vector(2); n > 0 {
+		return nil, errors.New("dictionary-encoded Arrow columns are not supported")
+	}
+
+	blocks, n := footer.structs(3, 24)
+	batches = make([]*RecordBatch, 0, n)
+	for i := 0; i < n; i++ {
+		offset := int(binary.LittleEndian.Uint64(blocks[24*i:]))
+		metaLen := int(binary.LittleEndian.Uint32(blocks[24*i+8:]))
+		bodyLen := int(binary.LittleEndian.Uint64(blocks[24*i+16:]))
+
+		meta := fbBytes(data, offset, metaLen)
+		if fbU32(meta, 0) == 0xffffffff {
+			meta = fbBytes(meta, 8, len(meta)-8)
+		} else {
+			meta = fbBytes(meta, 4, len(meta)-4)
+		}
+		msg := fbRoot(meta)
+		if msg.scalar(1, 1) != arrowHeaderRecordBatch {
+			return nil, fmt.Errorf("unexpected Arrow message type %d", msg.scalar(1, 1))
+		}
+		header, _ := msg.table(2)
+		batch, err := decodeArrowBatch(header, sources, fbBytes(data, offset+metaLen, bodyLen))
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+func decodeArrowSchema(schema fbRef) ([]arrowSource, error) {
+	if schema.scalar(0, 2) != 0 {
+		return nil, errors.New("big-endian Arrow files are not supported")
+	}
+
+	fields := schema.tables(1)
+	sources := make([]arrowSource, len(fields))
+	for i, f := range fields {
+		src := arrowSource{typeID: int(f.scalar(2, 1))}
+		src.field = ArrowField{Name: f.str(0), Nullable: f.scalar(1, 1) != 0}
+		typ, _ := f.table(3)
+
+		switch src.typeID {
+		case arrowTypeInt:
+			src.field.Type = ArrowInt64
+			src.bitWidth = int(typ.scalar(0, 4))
+			src.signed = typ.scalar(1, 1) != 0
+			if src.bitWidth != 8 && src.bitWidth != 16 && src.bitWidth != 32 && src.bitWidth != 64 {
+				return nil, fmt.Errorf("column %s: unsupported integer width %d", src.field.Name, src.bitWidth)
+			}
+		case arrowTypeFloatingPoint:
+			src.field.Type = ArrowFloat64
+			src.unit = int(typ.scalar(0, 2))
+			if src.unit == 0 {
+				return nil, fmt.Errorf("column %s: half-precision floats are not supported", src.field.Name)
+			}
+		case arrowTypeUtf8, arrowTypeLargeUtf8:
+			src.field.Type = ArrowString
+		case arrowTypeBool:
+			src.field.Type = ArrowBool
+		case arrowTypeDate, arrowTypeTimestamp:
+			src.field.Type = ArrowTimestamp
+			src.unit = int(typ.scalar(0, 2))
+		case arrowTypeDuration:
+			src.field.Type = ArrowDuration
+			src.unit = int(typ.scalar(0, 2))
+		default:
+			return nil, fmt.Errorf("column %s: unsupported Arrow type %d", src.field.Name, src.typeID)
+		}
+		sources[i] = src
+	}
+	return sources, nil
+}
+
+func decodeArrowBatch(header fbRef, sources []arrowSource, body []byte) (*RecordBatch, error) {
+	if _, ok := header.table(3); ok {
+		return nil, errors.New("compressed Arrow files are not supported")
+	}
+
+	nodes, nodeCount := header.structs(1, 16)
+	buffers, bufferCount := header.structs(2, 16)
+	if nodeCount != len(sources) {
+		return nil, errors.New("Arrow record batch does not match its schema")
+	}
+
+	next := 0
+	buffer := func() []byte {
+		if next >= bufferCount {
+			panic(errFlatBuffer)
+		}
+		offset := int(binary.LittleEndian.Uint64(buffers[16*next:]))
+		length := int(binary.LittleEndian.Uint64(buffers[16*next+8:]))
+		next++
+		return fbBytes(body, offset, length)
+	}
+
+	batch := &RecordBatch{Length: int(header.scalar(0, 8)), Columns: make([]*ArrowColumn, len(sources))}
+	if batch.Length < 0 {
+		return nil, errArrow
+	}
+	for i, src := range sources {
+		n := int(binary.LittleEndian.Uint64(nodes[16*i:]))
+		col := &ArrowColumn{Field: src.field, Length: n, NullCount: int(binary.LittleEndian.Uint64(nodes[16*i+8:]))}
+		if n != batch.Length || col.NullCount < 0 || col.NullCount > n {
+			return nil, errArrow
+		}
+
+		if validity := buffer(); col.NullCount > 0 {
+			if len(validity) < (n+7)/8 {
+				return nil, errArrow
+			}
+			col.Validity = append([]byte(nil), validity[:(n+7)/8]...)
+		}
+
+		switch src.typeID {
+		case arrowTypeUtf8:
+			raw := arrowValues(buffer(), 4, n+1)
+			col.Offsets = make([]int32, n+1)
+			for k := range col.Offsets {
+				col.Offsets[k] = int32(binary.LittleEndian.Uint32(raw[4*k:]))
+			}
+			col.Data = buffer()
+		case arrowTypeLargeUtf8:
+			raw := arrowValues(buffer(), 8, n+1)
+			col.Offsets = make([]int32, n+1)
+			for k := range col.Offsets {
+				off := binary.LittleEndian.Uint64(raw[8*k:])
+				if off > math.MaxInt32 {
+					return nil, fmt.Errorf("column %s: string data over 2GB is not supported", src.field.Name)
+				}
+				col.Offsets[k] = int32(off)
+			}
+			col.Data = buffer()
+		case arrowTypeBool:
+			col.Data = append([]byte(nil), arrowValues(buffer(), 1, (n+7)/8)...)
+		default:
+			col.Data = convertArrowValues(src, buffer(), n)
+		}
+		if len(col.Offsets) > 0 && col.Offsets[0] < 0 {
+			return nil, errArrow
+		}
+		for k := 1; k < len(col.Offsets); k++ {
+			if col.Offsets[k] < col.Offsets[k-1] || int(col.Offsets[k]) > len(col.Data) {
+				return nil, errArrow
+			}
+		}
+		batch.Columns[i] = col
+	}
+	return batch, nil
+}
+
+// convertArrowValues re-encodes fixed-width values as 64-bit values in the
+// canonical unit for the column type.
+func convertArrowValues(src arrowSource, raw []byte, n int) []byte {
+	width := 8
+	switch {
+	case src.typeID == arrowTypeInt:
+		width = src.bitWidth / 8
+	case src.typeID == arrowTypeFloatingPoint && src.unit == 1:
+		width = 4
+	case src.typeID == arrowTypeDate && src.unit == 0:
+		width = 4
+	}
+	raw = arrowValues(raw, width, n)
+
+	out := make([]byte, 8*n)
+	for i := 0; i < n; i++ {
+		var v uint64
+		switch src.typeID {
+		case arrowTypeInt:
+			v = arrowIntAt(raw[width*i:], width, src.signed)
+		case arrowTypeFloatingPoint:
+			if width == 4 {
+				v = math.Float64bits(float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))))
+			} else {
+				v = binary.LittleEndian.Uint64(raw[8*i:])
+			}
+		case arrowTypeDate:
+			if src.unit == 0 {
+				v = uint64(int64(int32(binary.LittleEndian.Uint32(raw[4*i:]))) * 86400 * 1e6)
+			} else {
+				v = uint64(int64(binary.LittleEndian.Uint64(raw[8*i:])) * 1e3)
+			}
+		case arrowTypeTimestamp:
+			v = uint64(scaleArrowTime(int64(binary.LittleEndian.Uint64(raw[8*i:])), src.unit, 2))
+		case arrowTypeDuration:
+			v = uint64(scaleArrowTime(int64(binary.LittleEndian.Uint64(raw[8*i:])), src.unit, 3))
+		}
+		binary.LittleEndian.PutUint64(out[8*i:], v)
+	}
+	return out
+}
+
+// arrowValues returns the first n values of width bytes in buf, panicking
+// with errArrow when the buffer is too short for the batch length.
+func arrowValues(buf []byte, width, n int) []byte {
+	if n < 0 || n > len(buf)/width {
+		panic(errArrow)
+	}
+	return buf[:width*n]
+}
+
+func arrowIntAt(b []byte, width int, signed bool) uint64 {
+	switch width {
+	case 1:
+		if signed {
+			return uint64(int64(int8(b[0])))
+		}
+		return uint64(b[0])
+	case 2:
+		v := binary.LittleEndian.Uint16(b)
+		if signed {
+			return uint64(int64(int16(v)))
+		}
+		return uint64(v)
+	case 4:
+		v := binary.LittleEndian.Uint32(b)
+		if signed {
+			return uint64(int64(int32(v)))
+		}
+		return uint64(v)
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// scaleArrowTime converts v between Arrow time units (0 = seconds through
+// 3 = nanoseconds).
+func scaleArrowTime(v int64, from, to int) int64 {
+	for ; from < to; from++ {
+		v *= 1000
+	}
+	for ; from > to; from-- {
+		v /= 1000
+	}
+	return v
+}
@@ -0,0 +1,408 @@
+package plygo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ArrowTrade struct {
+	Symbol  string
+	Qty     uint8
+	Price   float64
+	Filled  bool
+	At      time.Time
+	Latency time.Duration
+	Note    *string `plygo:"note"`
+	Skipped string  `plygo:"-"`
+}
+
+func arrowTrades() []ArrowTrade {
+	note := "late"
+	at := time.Date(2024, 3, 5, 9, 30, 0, 123456000, time.UTC)
+	return []ArrowTrade{
+		{"AAPL", 10, 171.5, true, at, 3 * time.Millisecond, nil, "x"},
+		{"MSFT", 200, 402.25, false, at.Add(time.Hour), time.Second, &note, "y"},
+		{"", 0, 0, false, time.Unix(0, 0).UTC(), 0, nil, "z"},
+	}
+}
+
+func TestRecordBatches(t *testing.T) {
+	batches := From(arrowTrades()).RecordBatches(2)
+	if len(batches) != 2 || batches[0].Length != 2 || batches[1].Length != 1 {
+		t.Fatalf("Expected batches of 2 and 1 rows, got %d batches", len(batches))
+	}
+
+	fields := batches[0].Fields()
+	expected := []ArrowField{
+		{"Symbol", ArrowString, false},
+		{"Qty", ArrowInt64, false},
+		{"Price", ArrowFloat64, false},
+		{"Filled", ArrowBool, false},
+		{"At", ArrowTimestamp, false},
+		{"Latency", ArrowDuration, false},
+		{"note", ArrowString, true},
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected %v, got %v", expected, fields)
+	}
+
+	note := batches[0].Columns[6]
+	if note.NullCount != 1 || !note.IsNull(0) || note.IsNull(1) || note.Value(1) != "late" {
+		t.Errorf("Unexpected note column %+v", note)
+	}
+	if !bytes.Equal(note.Validity, []byte{0b10}) {
+		t.Errorf("Expected validity bitmap 0b10, got %b", note.Validity)
+	}
+	if batches[0].Columns[0].Validity != nil {
+		t.Error("Expected no validity bitmap without nulls")
+	}
+	if got := batches[0].Columns[1].Value(1); got != int64(200) {
+		t.Errorf("Expected int64 200, got %v (%T)", got, got)
+	}
+}
+
+func TestArrowRoundTrip(t *testing.T) {
+	trades := arrowTrades()
+
+	var buf bytes.Buffer
+	if err := From(trades).WriteArrow(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("ARROW1\x00\x00")) || !bytes.HasSuffix(data, []byte("ARROW1")) {
+		t.Fatal("Expected Arrow magic at both ends")
+	}
+
+	p, err := FromArrow[ArrowTrade](bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := range trades {
+		trades[i].Skipped = ""
+	}
+	if !reflect.DeepEqual(p.Collect(), trades) {
+		t.Errorf("Round trip mismatch:\n%+v\n%+v", trades, p.Collect())
+	}
+}
+
+func TestArrowPointersAndUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	if err := From(arrowTrades()).WriteArrow(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	p, err := FromArrow[*ArrowTrade](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	trades := arrowTrades()
+	got := p.Collect()
+	if len(got) != len(trades) || got[0] == nil || got[0].Symbol != trades[0].Symbol || got[1].Price != trades[1].Price {
+		t.Errorf("Expected decoded pointers, got %v", got)
+	}
+
+	if _, err := FromArrow[float64](bytes.NewReader(buf.Bytes())); err == nil || !strings.Contains(err.Error(), "cannot read rows into float64") {
+		t.Errorf("Expected unsupported type error, got %v", err)
+	}
+}
+
+func TestArrowMaps(t *testing.T) {
+	records := FromRecords([]map[string]any{
+		{"id": 1, "score": 1.5, "tag": "a"},
+		{"id": 2, "score": 2, "tag": nil},
+	})
+
+	var buf bytes.Buffer
+	if err := records.WriteArrow(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	p, err := FromArrow[map[string]any](&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []map[string]any{
+		{"id": int64(1), "score": 1.5, "tag": "a"},
+		{"id": int64(2), "score": 2.0, "tag": nil},
+	}
+	if !reflect.DeepEqual(p.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, p.Collect())
+	}
+	if !reflect.DeepEqual(p.FieldNames(), []string{"id", "score", "tag"}) {
+		t.Errorf("Unexpected columns %v", p.FieldNames())
+	}
+}
+
+func TestArrowEmptyAndMalformed(t *testing.T) {
+	var buf bytes.Buffer
+	if err := From([]ArrowTrade{}).WriteArrow(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p, err := FromArrow[ArrowTrade](bytes.NewReader(buf.Bytes()))
+	if err != nil || p.Count() != 0 {
+		t.Errorf("Expected empty pipeline, got %v, %v", p, err)
+	}
+
+	data := buf.Bytes()
+	corrupt := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(corrupt[len(corrupt)-10:], 1<<20)
+	if _, err := ReadArrow(bytes.NewReader(corrupt)); err == nil {
+		t.Error("Expected error for corrupt footer length")
+	}
+	if _, err := ReadArrow(bytes.NewReader([]byte("not arrow at all, really"))); err == nil {
+		t.Error("Expected error for missing magic")
+	}
+}
+
+func TestConvertArrowValues(t *testing.T) {
+	raw := []byte{0xff, 0xff, 0x02, 0x00}
+	got := convertArrowValues(arrowSource{typeID: arrowTypeInt, bitWidth: 16, signed: true}, raw, 2)
+	col := &ArrowColumn{Field: ArrowField{Type: ArrowInt64}, Length: 2, Data: got}
+	if col.Value(0) != int64(-1) || col.Value(1) != int64(2) {
+		t.Errorf("Expected [-1 2], got [%v %v]", col.Value(0), col.Value(1))
+	}
+
+	days := make([]byte, 4)
+	binary.LittleEndian.PutUint32(days, 19787)
+	got = convertArrowValues(arrowSource{typeID: arrowTypeDate, unit: 0}, days, 1)
+	col = &ArrowColumn{Field: ArrowField{Type: ArrowTimestamp}, Length: 1, Data: got}
+	if want := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC); col.Value(0) != want {
+		t.Errorf("Expected %v, got %v", want, col.Value(0))
+	}
+
+	if scaleArrowTime(5, 0, 3) != 5e9 || scaleArrowTime(5e9, 3, 2) != 5e6 {
+		t.Error("Unexpected time unit scaling")
+	}
+}
+
+func TestFlatBufferLayout(t *testing.T) {
+	buf := fbFinish(fbTable{
+		fbScalar(2, 7),
+		fbChild(fbString("name")),
+		fbScalar(8, 1<<40),
+		nil,
+		fbChild(fbVector{fbTable{fbBool(true)}, fbTable{}}),
+		fbChild(fbStructs{n: 1, data: make([]byte, 16)}),
+	})
+
+	if len(buf)%8 != 0 {
+		t.Errorf("Expected buffer padded to 8 bytes, got %d", len(buf))
+	}
+
+	root := fbRoot(buf)
+	if root.pos%8 != 0 || root.field(2)%8 != 0 {
+		t.Error("Expected 8-byte fields to be 8-byte aligned")
+	}
+	if root.scalar(0, 2) != 7 || root.scalar(2, 8) != 1<<40 || root.field(3) != 0 {
+		t.Error("Unexpected scalar fields")
+	}
+	if root.str(1) != "name" {
+		t.Errorf("Expected name, got %q", root.str(1))
+	}
+	if tables := root.tables(4); len(tables) != 2 || tables[0].scalar(0, 1) != 1 || tables[1].field(0) != 0 {
+		t.Error("Unexpected table vector")
+	}
+	if start, n := root.vector(5); n != 1 || start%8 != 0 {
+		t.Errorf("Expected one aligned struct, got n=%d at %d", n, start)
+	}
+}
+
+// TestArrowLayout checks a small file against the IPC file format spec:
+// magic, encapsulated messages with a continuation marker, the end-of-stream
+// marker, and a footer pointing at the record batch.
+func TestArrowLayout(t *testing.T) {
+	type Row struct {
+		ID int64
+	}
+	var buf bytes.Buffer
+	if err := From([]Row{{7}, {-1}}).WriteArrow(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data := buf.Bytes()
+
+	if !bytes.HasPrefix(data, []byte("ARROW1\x00\x00")) || !bytes.HasSuffix(data, []byte("ARROW1")) {
+		t.Fatal("Expected Arrow magic at both ends")
+	}
+
+	// message returns the flatbuffer of the encapsulated message at pos and
+	// the position right after it.
+	message := func(pos int) (fbRef, int) {
+		t.Helper()
+		if binary.LittleEndian.Uint32(data[pos:]) != 0xffffffff {
+			t.Fatalf("Expected a continuation marker at %d", pos)
+		}
+		n := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		if (8+n)%8 != 0 {
+			t.Errorf("Expected message metadata padded to 8 bytes, got %d", n)
+		}
+		msg := fbRoot(data[pos+8 : pos+8+n])
+		// MetadataVersion V5 is 4
+		if msg.scalar(0, 2) != 4 {
+			t.Errorf("Expected metadata version V5, got %d", msg.scalar(0, 2))
+		}
+		return msg, pos + 8 + n
+	}
+
+	// MessageHeader 1 is Schema; Type 2 is Int
+	schemaMsg, pos := message(8)
+	schema, _ := schemaMsg.table(2)
+	if schemaMsg.scalar(1, 1) != 1 || schema.scalar(0, 2) != 0 {
+		t.Fatalf("Expected a little-endian schema message")
+	}
+	fields := schema.tables(1)
+	if len(fields) != 1 || fields[0].str(0) != "ID" || fields[0].scalar(1, 1) != 0 || fields[0].scalar(2, 1) != 2 {
+		t.Fatalf("Expected one non-nullable Int field ID")
+	}
+	if intType, _ := fields[0].table(3); intType.scalar(0, 4) != 64 || intType.scalar(1, 1) != 1 {
+		t.Errorf("Expected a signed 64-bit int")
+	}
+
+	// MessageHeader 3 is RecordBatch; nodes are (length, null_count) and
+	// buffers (offset, length) structs of two int64s
+	batchStart := pos
+	batchMsg, pos := message(pos)
+	batch, _ := batchMsg.table(2)
+	if batchMsg.scalar(1, 1) != 3 || batch.scalar(0, 8) != 2 || batchMsg.scalar(3, 8) != 16 {
+		t.Fatalf("Expected a 2-row record batch with a 16-byte body")
+	}
+	if nodes, n := batch.structs(1, 16); n != 1 || binary.LittleEndian.Uint64(nodes) != 2 || binary.LittleEndian.Uint64(nodes[8:]) != 0 {
+		t.Errorf("Expected one node of 2 rows and no nulls")
+	}
+	buffers, n := batch.structs(2, 16)
+	if n != 2 || binary.LittleEndian.Uint64(buffers[8:]) != 0 || binary.LittleEndian.Uint64(buffers[24:]) != 16 {
+		t.Fatalf("Expected an empty validity buffer and a 16-byte data buffer")
+	}
+	body := data[pos : pos+16]
+	want := []byte{7, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if !bytes.Equal(body, want) {
+		t.Errorf("Expected body % x, got % x", want, body)
+	}
+	pos += 16
+
+	if !bytes.Equal(data[pos:pos+8], []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}) {
+		t.Errorf("Expected the end-of-stream marker at %d", pos)
+	}
+	pos += 8
+
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-10:]))
+	if pos+footerLen+10 != len(data) {
+		t.Fatalf("Expected the footer right after the end-of-stream marker")
+	}
+	footer := fbRoot(data[pos : pos+footerLen])
+	blocks, n := footer.structs(3, 24)
+	if footer.scalar(0, 2) != 4 || n != 1 {
+		t.Fatalf("Expected a V5 footer with one block")
+	}
+	if offset := binary.LittleEndian.Uint64(blocks); offset != uint64(batchStart) {
+		t.Errorf("Expected the block at %d, got %d", batchStart, offset)
+	}
+	if bodyLen := binary.LittleEndian.Uint64(blocks[16:]); bodyLen != 16 {
+		t.Errorf("Expected a 16-byte block body, got %d", bodyLen)
+	}
+}
+
+// TestArrowReferenceFile reads an IPC file written by another Arrow
+// implementation, from testdata/arrow.
+func TestArrowReferenceFile(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "arrow", "custom_metadata.arrow"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer file.Close()
+
+	batches, err := ReadArrow(file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(batches) != 2 || batches[0].Length != 3 || batches[1].Length != 3 {
+		t.Fatalf("Expected two batches of 3 rows, got %d", len(batches))
+	}
+	fields := batches[0].Fields()
+	if fields[0].Name != "id" || fields[0].Type != ArrowInt64 || fields[1].Name != "name" || fields[1].Type != ArrowString {
+		t.Errorf("Unexpected fields %+v", fields)
+	}
+
+	p, err := FromRecordBatches[map[string]any](batches...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []map[string]any{
+		{"id": int64(1), "name": "a"}, {"id": int64(2), "name": "b"}, {"id": int64(3), "name": "c"},
+		{"id": int64(4), "name": "d"}, {"id": int64(5), "name": "e"}, {"id": int64(6), "name": "f"},
+	}
+	if !reflect.DeepEqual(p.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, p.Collect())
+	}
+}
+
+func TestArrowInconsistentBatch(t *testing.T) {
+	p := From(arrowTrades())
+	fields, _ := p.arrowFields()
+	cases := map[string]func(b *RecordBatch){
+		"negative first offset":   func(b *RecordBatch) { b.Columns[0].Offsets[0] = -1 },
+		"node longer than batch":  func(b *RecordBatch) { b.Columns[1].Length = 5 },
+		"node shorter than batch": func(b *RecordBatch) { b.Length = 4 },
+		"short data buffer":       func(b *RecordBatch) { b.Columns[2].Data = b.Columns[2].Data[:8] },
+		"short validity buffer": func(b *RecordBatch) {
+			b.Columns[6].Length, b.Length = 9, 9
+			for _, col := range b.Columns[:6] {
+				col.Length = 9
+			}
+		},
+	}
+	for name, corrupt := range cases {
+		batches := p.RecordBatches(0)
+		corrupt(batches[0])
+		var buf bytes.Buffer
+		if err := writeArrowFile(&buf, fields, batches); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := ReadArrow(bytes.NewReader(buf.Bytes())); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestArrowCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	if err := From(arrowTrades()).WriteArrow(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data := buf.Bytes()
+
+	extremes := []uint64{0, 1, 0x7f, 0xff, 1 << 31, 1<<63 - 1, 1 << 63, 1<<64 - 1}
+	seed := uint32(7)
+	next := func() int {
+		seed = seed*1664525 + 1013904223
+		return int(seed >> 8)
+	}
+	for i := 0; i < 20000; i++ {
+		corrupt := append([]byte(nil), data...)
+		for k := next()%3 + 1; k > 0; k-- {
+			pos := next() % len(corrupt)
+			if next()%2 == 0 && pos+8 <= len(corrupt) {
+				binary.LittleEndian.PutUint64(corrupt[pos:], extremes[next()%len(extremes)])
+			} else {
+				corrupt[pos] = byte(next())
+			}
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("Panic on corrupted input %d: %v", i, r)
+				}
+			}()
+			FromArrow[ArrowTrade](bytes.NewReader(corrupt))
+			FromArrow[map[string]any](bytes.NewReader(corrupt))
+		}()
+	}
+}
@@ -0,0 +1,173 @@
+package plygo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Period is a calendar unit used by GroupByPeriod.
+type Period int
+
+const (
+	PeriodDay Period = iota
+	PeriodWeek
+	PeriodMonth
+	PeriodQuarter
+	PeriodYear
+)
+
+// GroupByBins groups rows into half-open ranges [edges[i], edges[i+1]).
+// Values below the first edge or at/above the last edge get their own
+// open-ended buckets, so no rows are dropped, and NaN values share a "NaN"
+// bucket. Keys are range labels such as "[18, 35)".
+func (p *Pipeline[T]) GroupByBins(field string, edges ...float64) *Grouping[T] {
+	sorted := append([]float64(nil), edges...)
+	sort.Float64s(sorted)
+
+	return &Grouping[T]{
+		pipeline: p,
+		field:    field,
+		bucket: func(v any) any {
+			f := toFloat64(v)
+			if math.IsNaN(f) {
+				return "NaN"
+			}
+			return binLabel(f, sorted)
+		},
+	}
+}
+
+// GroupByWidth groups numeric values into buckets of equal width starting
+// at zero, e.g. width 10 gives "[0, 10)", "[10, 20)", ... NaN values share
+// a "NaN" bucket.
+func (p *Pipeline[T]) GroupByWidth(field string, width float64) *Grouping[T] {
+	return &Grouping[T]{
+		pipeline: p,
+		field:    field,
+		bucket: func(v any) any {
+			if width <= 0 {
+				return valueKey(v)
+			}
+			f := toFloat64(v)
+			if math.IsNaN(f) {
+				return "NaN"
+			}
+			lower := math.Floor(f/width) * width
+			return rangeLabel(lower, lower+width)
+		},
+	}
+}
+
+// GroupByTime groups time.Time values into fixed-size buckets of each
+// value's wall clock, so a 24h bucket is a calendar day in the value's own
+// location. Keys are the formatted start of each bucket.
+func (p *Pipeline[T]) GroupByTime(field string, d time.Duration) *Grouping[T] {
+	return &Grouping[T]{
+		pipeline: p,
+		field:    field,
+		bucket: func(v any) any {
+			t, ok := toTime(v)
+			if !ok || d <= 0 {
+				return valueKey(v)
+			}
+			return wallTruncate(t, d).Format(durationLayout(d))
+		},
+	}
+}
+
+// GroupByPeriod groups time.Time values by calendar period in each value's
+// own location. Keys look like "2024-03-15", "2024-W11", "2024-03",
+// "2024-Q1" and "2024".
+func (p *Pipeline[T]) GroupByPeriod(field string, period Period) *Grouping[T] {
+	return &Grouping[T]{
+		pipeline: p,
+		field:    field,
+		bucket: func(v any) any {
+			t, ok := toTime(v)
+			if !ok {
+				return valueKey(v)
+			}
+			return periodLabel(t, period)
+		},
+	}
+}
+
+func binLabel(v float64, edges []float64) string {
+	if len(edges) == 0 {
+		return rangeLabel(math.Inf(-1), math.Inf(1))
+	}
+	if v < edges[0] {
+		return rangeLabel(math.Inf(-1), edges[0])
+	}
+	for i := 1; i < len(edges); i++ {
+		if v < edges[i] {
+			return rangeLabel(edges[i-1], edges[i])
+		}
+	}
+	return rangeLabel(edges[len(edges)-1], math.Inf(1))
+}
+
+func rangeLabel(lower, upper float64) string {
+	open := "["
+	if math.IsInf(lower, -1) {
+		open = "("
+	}
+	return fmt.Sprintf("%s%s, %s)", open, formatEdge(lower), formatEdge(upper))
+}
+
+func formatEdge(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// wallTruncate truncates t to a multiple of d counted from the zero time on
+// t's wall clock, rather than on absolute time as time.Truncate does.
+func wallTruncate(t time.Time, d time.Duration) time.Time {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC).Truncate(d)
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), t.Location())
+}
+
+func durationLayout(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return "2006-01-02"
+	case d%time.Minute == 0:
+		return "2006-01-02 15:04"
+	}
+	return "2006-01-02 15:04:05"
+}
+
+func periodLabel(t time.Time, period Period) string {
+	switch period {
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case PeriodMonth:
+		return t.Format("2006-01")
+	case PeriodQuarter:
+		return fmt.Sprintf("%04d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	case PeriodYear:
+		return t.Format("2006")
+	}
+	return t.Format("2006-01-02")
+}
+
+func toTime(v any) (time.Time, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case *time.Time:
+		if val != nil {
+			return *val, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,142 @@
+package plygo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// orderRecords holds timestamped rows for the time buckets, which the
+// Person fixture has no field for.
+func orderRecords() []map[string]any {
+	day := func(d, h int) time.Time {
+		return time.Date(2024, time.March, d, h, 0, 0, 0, time.UTC)
+	}
+	return []map[string]any{
+		{"Customer": "Alice", "Price": 4.5, "CreatedAt": day(4, 9)},
+		{"Customer": "Bob", "Price": 12.0, "CreatedAt": day(4, 17)},
+		{"Customer": "Charlie", "Price": 19.99, "CreatedAt": day(11, 8)},
+		{"Customer": "Diana", "Price": 35.0, "CreatedAt": day(18, 12)},
+		{"Customer": "Eve", "Price": 20.0, "CreatedAt": day(31, 23)},
+	}
+}
+
+func TestGroupByBins(t *testing.T) {
+	counts := From(samplePeople()).GroupByBins("Age", 18, 30, 40).Count()
+
+	expected := map[any]int{
+		"[18, 30)":   3,
+		"[30, 40)":   3,
+		"[40, +Inf)": 1,
+	}
+	if len(counts) != len(expected) {
+		t.Errorf("Expected %d buckets, got %v", len(expected), counts)
+	}
+	for key, count := range expected {
+		if counts[key] != count {
+			t.Errorf("Expected %d in %v, got %d", count, key, counts[key])
+		}
+	}
+
+	avg := From(samplePeople()).GroupByBins("Age", 18, 30, 40).Avg("Salary")
+	if avg["[18, 30)"] != 64000 {
+		t.Errorf("Expected average salary 64000 for [18, 30), got %f", avg["[18, 30)"])
+	}
+}
+
+func TestGroupByBins_BelowFirstEdge(t *testing.T) {
+	counts := From(samplePeople()).GroupByBins("Age", 26, 50).Count()
+	if counts["(-Inf, 26)"] != 2 {
+		t.Errorf("Expected 2 values below first edge, got %v", counts)
+	}
+}
+
+func TestGroupByWidth(t *testing.T) {
+	sums := From(samplePeople()).GroupByWidth("Salary", 10000).Sum("Salary")
+
+	expected := map[any]float64{
+		"[60000, 70000)":  122000,
+		"[70000, 80000)":  145000,
+		"[80000, 90000)":  85000,
+		"[90000, 100000)": 185000,
+	}
+	if len(sums) != len(expected) {
+		t.Errorf("Expected %d buckets, got %v", len(expected), sums)
+	}
+	for key, sum := range expected {
+		if sums[key] != sum {
+			t.Errorf("Expected %f in %v, got %f", sum, key, sums[key])
+		}
+	}
+}
+
+func TestGroupByBins_NaNAndEmpty(t *testing.T) {
+	people := samplePeople()
+	people[0].Salary = math.NaN()
+	people[1].Salary = math.NaN()
+
+	bins := From(people).GroupByBins("Salary", 70000).Count()
+	if bins["NaN"] != 2 || bins["[70000, +Inf)"] != 4 || bins["(-Inf, 70000)"] != 1 {
+		t.Errorf("Expected NaN salaries in their own bucket, got %v", bins)
+	}
+	widths := From(people).GroupByWidth("Salary", 10000).Count()
+	if widths["NaN"] != 2 {
+		t.Errorf("Expected NaN salaries in their own bucket, got %v", widths)
+	}
+
+	if counts := From([]Person{}).GroupByBins("Age", 18, 30).Count(); len(counts) != 0 {
+		t.Errorf("Expected no buckets for empty input, got %v", counts)
+	}
+}
+
+func TestGroupByTime(t *testing.T) {
+	groups := From(orderRecords()).GroupByTime("CreatedAt", 24*time.Hour).Groups()
+
+	if len(groups) != 4 {
+		t.Fatalf("Expected 4 daily buckets, got %d", len(groups))
+	}
+	if groups[0].Key != "2024-03-04" || groups[0].Rows.Count() != 2 {
+		t.Errorf("Expected 2 orders on 2024-03-04, got %v with %d", groups[0].Key, groups[0].Rows.Count())
+	}
+
+	hourly := From(orderRecords()).GroupByTime("CreatedAt", 6*time.Hour).Count()
+	if hourly["2024-03-04 06:00"] != 1 || hourly["2024-03-04 12:00"] != 1 {
+		t.Errorf("Unexpected 6h buckets: %v", hourly)
+	}
+}
+
+func TestGroupByTime_Location(t *testing.T) {
+	zone := time.FixedZone("UTC+5", 5*60*60)
+	records := []map[string]any{
+		{"CreatedAt": time.Date(2024, 3, 15, 1, 0, 0, 0, zone)},
+		{"CreatedAt": time.Date(2024, 3, 15, 23, 0, 0, 0, zone)},
+		{"CreatedAt": time.Date(2024, 3, 16, 4, 59, 0, 0, zone)},
+	}
+
+	days := From(records).GroupByTime("CreatedAt", 24*time.Hour).Count()
+	if days["2024-03-15"] != 2 || days["2024-03-16"] != 1 {
+		t.Errorf("Expected days on the local wall clock, got %v", days)
+	}
+
+	hours := From(records).GroupByTime("CreatedAt", 6*time.Hour).Count()
+	if hours["2024-03-15 00:00"] != 1 || hours["2024-03-15 18:00"] != 1 || hours["2024-03-16 00:00"] != 1 {
+		t.Errorf("Expected 6h buckets on the local wall clock, got %v", hours)
+	}
+}
+
+func TestGroupByPeriod(t *testing.T) {
+	weeks := From(orderRecords()).GroupByPeriod("CreatedAt", PeriodWeek).Count()
+	if weeks["2024-W10"] != 2 || weeks["2024-W13"] != 1 {
+		t.Errorf("Unexpected weekly buckets: %v", weeks)
+	}
+
+	months := From(orderRecords()).GroupByPeriod("CreatedAt", PeriodMonth).Count()
+	if months["2024-03"] != 5 {
+		t.Errorf("Expected 5 orders in 2024-03, got %v", months)
+	}
+
+	quarters := From(orderRecords()).GroupByPeriod("CreatedAt", PeriodQuarter).Sum("Price")
+	if quarters["2024-Q1"] != 91.49 {
+		t.Errorf("Expected 91.49 in 2024-Q1, got %v", quarters)
+	}
+}
@@ -0,0 +1,126 @@
+package plygo
+
+// WithColumn returns a map pipeline with every field of p plus a column
+// computed by fn. An existing column with the same name is replaced in
+// place.
+func (p *Pipeline[T]) WithColumn(name string, fn func(T) any) *Pipeline[map[string]any] {
+	result := p.records()
+	for i, item := range p.data {
+		result.data[i][name] = fn(item)
+	}
+	result.columns = addColumn(result.columns, name)
+	return result
+}
+
+// Rename returns a map pipeline with field oldName renamed to newName.
+func (p *Pipeline[T]) Rename(oldName, newName string) *Pipeline[map[string]any] {
+	return renameColumn(p.records(), oldName, newName)
+}
+
+// Drop returns a map pipeline without the given fields.
+func (p *Pipeline[T]) Drop(fields ...string) *Pipeline[map[string]any] {
+	return dropColumns(p.records(), fields)
+}
+
+// Mutate returns a map pipeline with the selected fields plus a column
+// computed from each selected row.
+func (s *Selection[T]) Mutate(name string, fn func(row map[string]any) any) *Pipeline[map[string]any] {
+	result := s.records()
+	for _, row := range result.data {
+		row[name] = fn(row)
+	}
+	result.columns = addColumn(result.columns, name)
+	return result
+}
+
+// Rename returns a map pipeline of the selected fields with oldName renamed
+// to newName.
+func (s *Selection[T]) Rename(oldName, newName string) *Pipeline[map[string]any] {
+	return renameColumn(s.records(), oldName, newName)
+}
+
+// Drop returns a map pipeline of the selected fields without the given
+// fields.
+func (s *Selection[T]) Drop(fields ...string) *Pipeline[map[string]any] {
+	return dropColumns(s.records(), fields)
+}
+
+func (p *Pipeline[T]) records() *Pipeline[map[string]any] {
+	fields := p.FieldNames()
+
+	result := make([]map[string]any, len(p.data))
+	for i, item := range p.data {
+		row := make(map[string]any, len(fields)+1)
+		for _, field := range fields {
+			row[field] = getFieldValue(item, field)
+		}
+		result[i] = row
+	}
+
+	resultIdx := make([]int, len(p.data))
+	copy(resultIdx, p.originalIndex)
+
+	return &Pipeline[map[string]any]{data: result, originalIndex: resultIdx, columns: fields, sources: p.sources}
+}
+
+func (s *Selection[T]) records() *Pipeline[map[string]any] {
+	resultIdx := make([]int, len(s.pipeline.data))
+	copy(resultIdx, s.pipeline.originalIndex)
+
+	return &Pipeline[map[string]any]{
+		data:          s.execute(),
+		originalIndex: resultIdx,
+		columns:       append([]string(nil), s.fields...),
+		sources:       s.pipeline.sources,
+	}
+}
+
+func renameColumn(p *Pipeline[map[string]any], oldName, newName string) *Pipeline[map[string]any] {
+	for _, row := range p.data {
+		if val, found := row[oldName]; found {
+			delete(row, oldName)
+			row[newName] = val
+		}
+	}
+
+	columns := make([]string, len(p.columns))
+	for i, col := range p.columns {
+		if col == oldName {
+			col = newName
+		}
+		columns[i] = col
+	}
+	p.columns = columns
+	return p
+}
+
+func dropColumns(p *Pipeline[map[string]any], fields []string) *Pipeline[map[string]any] {
+	drop := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		drop[f] = true
+	}
+
+	for _, row := range p.data {
+		for _, f := range fields {
+			delete(row, f)
+		}
+	}
+
+	columns := make([]string, 0, len(p.columns))
+	for _, col := range p.columns {
+		if !drop[col] {
+			columns = append(columns, col)
+		}
+	}
+	p.columns = columns
+	return p
+}
+
+func addColumn(columns []string, name string) []string {
+	for _, col := range columns {
+		if col == name {
+			return columns
+		}
+	}
+	return append(columns, name)
+}
@@ -0,0 +1,107 @@
+package plygo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithColumn(t *testing.T) {
+	result := From(samplePeople()[:3]).
+		WithColumn("Bonus", func(p Person) any { return p.Salary / 10 })
+
+	if !reflect.DeepEqual(result.FieldNames(), []string{"Name", "Age", "City", "Salary", "Active", "Bonus"}) {
+		t.Errorf("Unexpected columns: %v", result.FieldNames())
+	}
+	if result.Collect()[1]["Bonus"] != 6000.0 {
+		t.Errorf("Expected bonus 6000, got %v", result.Collect()[1]["Bonus"])
+	}
+
+	chained := result.WithColumn("Total", func(row map[string]any) any {
+		return row["Salary"].(float64) + row["Bonus"].(float64)
+	})
+	if chained.Collect()[0]["Total"] != 82500.0 {
+		t.Errorf("Expected total 82500, got %v", chained.Collect()[0]["Total"])
+	}
+	if _, ok := result.Collect()[0]["Total"]; ok {
+		t.Error("WithColumn should not modify its source rows")
+	}
+}
+
+func TestWithColumn_KeepsIndices(t *testing.T) {
+	result := From(samplePeople()[:3]).
+		AtRow(3, 1).
+		WithColumn("Senior", func(p Person) any { return p.Age > 32 })
+
+	if !reflect.DeepEqual(result.Which(), []int{3, 1}) {
+		t.Errorf("Expected [3 1], got %v", result.Which())
+	}
+	if result.Collect()[0]["Senior"] != true {
+		t.Errorf("Expected Charlie to be senior, got %v", result.Collect()[0])
+	}
+}
+
+func TestWithColumn_Empty(t *testing.T) {
+	called := false
+	result := From([]Person{}).WithColumn("Bonus", func(p Person) any {
+		called = true
+		return p.Salary / 10
+	})
+
+	if result.Count() != 0 || called {
+		t.Errorf("Expected no rows and no calls, got %v", result.Collect())
+	}
+}
+
+func TestSelectionMutate(t *testing.T) {
+	result := From(samplePeople()[:3]).
+		Select("Name", "Salary").
+		Mutate("Monthly", func(row map[string]any) any { return row["Salary"].(float64) / 12 })
+
+	if !reflect.DeepEqual(result.FieldNames(), []string{"Name", "Salary", "Monthly"}) {
+		t.Errorf("Unexpected columns: %v", result.FieldNames())
+	}
+	if result.Collect()[0]["Monthly"] != 6250.0 {
+		t.Errorf("Expected 6250, got %v", result.Collect()[0]["Monthly"])
+	}
+	if _, ok := result.Collect()[0]["Age"]; ok {
+		t.Error("Age was not selected")
+	}
+}
+
+func TestRenameDrop(t *testing.T) {
+	renamed := From(samplePeople()[:3]).Rename("Salary", "Pay")
+	if !reflect.DeepEqual(renamed.FieldNames(), []string{"Name", "Age", "City", "Pay", "Active"}) {
+		t.Errorf("Unexpected columns: %v", renamed.FieldNames())
+	}
+	if renamed.Collect()[0]["Pay"] != 75000.0 {
+		t.Errorf("Expected Pay 75000, got %v", renamed.Collect()[0])
+	}
+
+	dropped := From(samplePeople()[:3]).Drop("Age")
+	if !reflect.DeepEqual(dropped.FieldNames(), []string{"Name", "City", "Salary", "Active"}) {
+		t.Errorf("Unexpected columns: %v", dropped.FieldNames())
+	}
+
+	selected := From(samplePeople()[:3]).Select("Name", "Age", "Salary").Drop("Name").Rename("Age", "Years")
+	if !reflect.DeepEqual(selected.FieldNames(), []string{"Years", "Salary"}) {
+		t.Errorf("Unexpected columns: %v", selected.FieldNames())
+	}
+}
+
+func TestWithColumn_Show(t *testing.T) {
+	output := captureOutput(func() {
+		From(samplePeople()[:3]).
+			WithColumn("Bonus", func(p Person) any { return p.Salary / 10 }).
+			Where("Bonus").GreaterThan(7000).
+			Show()
+	})
+
+	header := strings.Split(output, "\n")[1]
+	if !strings.Contains(header, "Bonus") || strings.Index(header, "Name") > strings.Index(header, "Bonus") {
+		t.Errorf("Expected Bonus as the last column, got %q", header)
+	}
+	if strings.Contains(output, "Bob") {
+		t.Error("Bob should be filtered out")
+	}
+}
@@ -0,0 +1,515 @@
+package plygo
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RowError describes a row that could not be decoded. Line is the 1-based
+// line in the input where the row starts.
+type RowError struct {
+	Line  int
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: field %s: cannot parse %q: %v", e.Line, e.Field, e.Value, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ReadReport collects the rows skipped while reading with SkipMalformed.
+type ReadReport struct {
+	Skipped []*RowError
+}
+
+type CSVConfig struct {
+	delimiter   rune
+	comment     rune
+	lazyQuotes  bool
+	header      bool
+	columns     []string
+	timeLayouts []string
+	skip        bool
+	report      *ReadReport
+	precision   int
+	null        string
+}
+
+type CSVOption func(*CSVConfig)
+
+// CSVDelimiter sets the field delimiter, e.g. '\t' or ';'.
+func CSVDelimiter(r rune) CSVOption {
+	return func(c *CSVConfig) { c.delimiter = r }
+}
+
+// CSVComment sets a character that starts comment lines.
+func CSVComment(r rune) CSVOption {
+	return func(c *CSVConfig) { c.comment = r }
+}
+
+// CSVLazyQuotes tolerates quotes appearing inside unquoted fields.
+func CSVLazyQuotes(lazy bool) CSVOption {
+	return func(c *CSVConfig) { c.lazyQuotes = lazy }
+}
+
+// CSVNoHeader reads a file without a header row. Columns are mapped to the
+// given names, or to the struct fields in order when none are given.
+func CSVNoHeader(columns ...string) CSVOption {
+	return func(c *CSVConfig) {
+		c.header = false
+		c.columns = columns
+	}
+}
+
+// CSVTimeLayouts sets the layouts tried, in order, when parsing time.Time
+// fields. Writers format times with the first layout.
+func CSVTimeLayouts(layouts ...string) CSVOption {
+	return func(c *CSVConfig) { c.timeLayouts = layouts }
+}
+
+// CSVFloatPrecision sets the number of decimals written for floats. The
+// default, -1, writes the shortest exact representation.
+func CSVFloatPrecision(n int) CSVOption {
+	return func(c *CSVConfig) { c.precision = n }
+}
+
+// CSVNull sets the text written for nil values. When reading, cells with
+// this text are treated as empty.
+func CSVNull(s string) CSVOption {
+	return func(c *CSVConfig) { c.null = s }
+}
+
+// SkipMalformed skips rows that fail to parse instead of stopping at the
+// first one. Skipped rows are recorded in report, which may be nil.
+func SkipMalformed(report *ReadReport) CSVOption {
+	return func(c *CSVConfig) {
+		c.skip = true
+		c.report = report
+	}
+}
+
+func defaultCSVConfig() *CSVConfig {
+	return &CSVConfig{
+		delimiter:   ',',
+		header:      true,
+		timeLayouts: []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"},
+		precision:   -1,
+	}
+}
+
+// FromCSV decodes CSV rows into a pipeline of structs, struct pointers or
+// map[string]any. Header names are matched to struct fields by `plygo` tag
+// or field name (falling back to a case-insensitive match); unknown columns
+// are ignored. For map rows every column is kept as a string. Original indices are the 1-based record
+// numbers in the file, so they stay meaningful when rows are skipped.
+func FromCSV[T any](r io.Reader, options ...CSVOption) (*Pipeline[T], error) {
+	config := defaultCSVConfig()
+	for _, opt := range options {
+		opt(config)
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = config.delimiter
+	reader.Comment = config.comment
+	reader.LazyQuotes = config.lazyQuotes
+
+	var zero T
+	typ := reflect.TypeOf(zero)
+	structType, err := recordType(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := config.columns
+	if config.header {
+		header, err := reader.Read()
+		if err == io.EOF {
+			return From([]T{}), nil
+		}
+		if err != nil {
+			return nil, csvRowError(err)
+		}
+		columns = header
+	}
+
+	var targets []fieldTarget
+	if structType != nil {
+		if len(columns) == 0 {
+			columns = structFieldKeys(structType)
+		}
+		targets = matchColumns(structType, columns)
+	}
+
+	result := make([]T, 0)
+	resultIdx := make([]int, 0)
+	record := 0
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		record++
+
+		var item T
+		if err != nil {
+			err = csvRowError(err)
+		} else if targets != nil {
+			line, _ := reader.FieldPos(0)
+			err = decodeRecord(recordValue(&item), row, targets, line, config)
+		} else if m, ok := any(&item).(*map[string]any); ok {
+			*m = make(map[string]any, len(row))
+			for i, val := range row {
+				if i >= len(columns) {
+					continue
+				}
+				if config.null != "" && val == config.null {
+					(*m)[columns[i]] = nil
+				} else {
+					(*m)[columns[i]] = val
+				}
+			}
+		}
+
+		if err != nil {
+			var rowErr *RowError
+			if !config.skip || !errors.As(err, &rowErr) {
+				return nil, err
+			}
+			if config.report != nil {
+				config.report.Skipped = append(config.report.Skipped, rowErr)
+			}
+			continue
+		}
+
+		result = append(result, item)
+		resultIdx = append(resultIdx, record)
+	}
+
+	p := &Pipeline[T]{data: result, originalIndex: resultIdx}
+	if typ != nil && typ.Kind() == reflect.Map {
+		p.columns = columns
+	}
+	return p, nil
+}
+
+type fieldTarget struct {
+	column int
+	field  int
+	name   string
+}
+
+// recordType returns the struct type the readers decode T into: T itself,
+// or the struct T points to. It is nil for map[string]any, and any other
+// type is an error rather than a pipeline of zero values.
+func recordType(typ reflect.Type) (reflect.Type, error) {
+	switch {
+	case typ == nil:
+	case typ.Kind() == reflect.Struct:
+		return typ, nil
+	case typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct:
+		return typ.Elem(), nil
+	case typ == reflect.TypeOf(map[string]any(nil)):
+		return nil, nil
+	}
+	return nil, fmt.Errorf("cannot read rows into %v: need a struct, a pointer to a struct or map[string]any", typ)
+}
+
+// recordValue returns the struct to fill for item, allocating it first when
+// T is a pointer.
+func recordValue[T any](item *T) reflect.Value {
+	v := reflect.ValueOf(item).Elem()
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func structFieldKeys(typ reflect.Type) []string {
+	keys := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).IsExported() {
+			if key := fieldKey(typ.Field(i)); key != "" {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+func matchColumns(typ reflect.Type, columns []string) []fieldTarget {
+	targets := make([]fieldTarget, 0, len(columns))
+	for c, col := range columns {
+		col = strings.TrimSpace(col)
+		match := -1
+		for i := 0; i < typ.NumField() && match < 0; i++ {
+			sf := typ.Field(i)
+			if sf.IsExported() && fieldKey(sf) == col {
+				match = i
+			}
+		}
+		for i := 0; i < typ.NumField() && match < 0; i++ {
+			sf := typ.Field(i)
+			if sf.IsExported() && fieldKey(sf) != "" && strings.EqualFold(fieldKey(sf), col) {
+				match = i
+			}
+		}
+		if match >= 0 {
+			targets = append(targets, fieldTarget{column: c, field: match, name: typ.Field(match).Name})
+		}
+	}
+	return targets
+}
+
+func decodeRecord(v reflect.Value, row []string, targets []fieldTarget, line int, config *CSVConfig) error {
+	for _, t := range targets {
+		if t.column >= len(row) {
+			continue
+		}
+		text := row[t.column]
+		if config.null != "" && text == config.null {
+			continue
+		}
+		if err := parseInto(v.Field(t.field), text, config.timeLayouts); err != nil {
+			return &RowError{Line: line, Field: t.name, Value: row[t.column], Err: err}
+		}
+	}
+	return nil
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// parseInto sets dst from its text form. Empty text leaves the zero value
+// (nil for pointers).
+func parseInto(dst reflect.Value, text string, timeLayouts []string) error {
+	if dst.Kind() == reflect.Ptr {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		elem := reflect.New(dst.Type().Elem())
+		if err := parseInto(elem.Elem(), text, timeLayouts); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+
+	if dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok && dst.Type() != timeType {
+			return u.UnmarshalText([]byte(text))
+		}
+	}
+
+	if dst.Kind() == reflect.String {
+		dst.SetString(text)
+		return nil
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	switch {
+	case dst.Type() == timeType:
+		var lastErr error
+		for _, layout := range timeLayouts {
+			t, err := time.Parse(layout, text)
+			if err == nil {
+				dst.Set(reflect.ValueOf(t))
+				return nil
+			}
+			lastErr = err
+		}
+		return lastErr
+	case dst.Type() == durationType:
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(text))
+	default:
+		return fmt.Errorf("unsupported field type %s", dst.Type())
+	}
+	return nil
+}
+
+func csvRowError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+	}
+	return err
+}
+
+// WriteCSV writes a header and one record per row, with fields in the same
+// order as Show. Struct fields use their `plygo` tag as header when set.
+func (p *Pipeline[T]) WriteCSV(w io.Writer, options ...CSVOption) error {
+	headers, fields := p.exportColumns()
+	return writeCSV(w, headers, len(p.data), func(i, j int) any {
+		return getFieldValue(p.data[i], fields[j])
+	}, options)
+}
+
+// WriteCSV writes the selected fields in selection order.
+func (s *Selection[T]) WriteCSV(w io.Writer, options ...CSVOption) error {
+	rows := s.execute()
+	return writeCSV(w, s.fields, len(rows), func(i, j int) any {
+		return rows[i][s.fields[j]]
+	}, options)
+}
+
+// exportColumns returns the header names and field names used when writing
+// a pipeline to a file: struct fields in declaration order (skipping
+// unexported and `plygo:"-"` fields), or the map columns.
+func (p *Pipeline[T]) exportColumns() (headers, fields []string) {
+	var zero T
+	typ := reflect.TypeOf(zero)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ != nil && typ.Kind() == reflect.Struct {
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			if key := fieldKey(sf); sf.IsExported() && key != "" {
+				headers = append(headers, key)
+				fields = append(fields, sf.Name)
+			}
+		}
+		return headers, fields
+	}
+
+	if len(p.columns) > 0 {
+		fields = append([]string(nil), p.columns...)
+	} else if rows, ok := any(p.data).([]map[string]any); ok {
+		fields = mapKeys(rows)
+	} else {
+		fields = p.FieldNames()
+	}
+	return fields, fields
+}
+
+func writeCSV(w io.Writer, headers []string, n int, cell func(i, j int) any, options []CSVOption) error {
+	config := defaultCSVConfig()
+	for _, opt := range options {
+		opt(config)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = config.delimiter
+
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	record := make([]string, len(headers))
+	for i := 0; i < n; i++ {
+		for j := range headers {
+			record[j] = formatCell(cell(i, j), config)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatCell is the file counterpart of formatValue: it keeps full
+// precision and writes values so that FromCSV can read them back.
+func formatCell(v any, config *CSVConfig) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return config.null
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return config.null
+	}
+
+	if rv.Type() == timeType {
+		layout := time.RFC3339
+		if len(config.timeLayouts) > 0 {
+			layout = config.timeLayouts[0]
+		}
+		return rv.Interface().(time.Time).Format(layout)
+	}
+	if rv.Type() == durationType {
+		return time.Duration(rv.Int()).String()
+	}
+	if m, ok := rv.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', config.precision, rv.Type().Bits())
+	default:
+		return fmt.Sprint(rv.Interface())
+	}
+}
@@ -0,0 +1,330 @@
+package plygo
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type CSVEvent struct {
+	Title    string    `plygo:"title"`
+	Attendee *int      `plygo:"attendees"`
+	When     time.Time `plygo:"date"`
+	Length   time.Duration
+	Internal string `plygo:"-"`
+}
+
+func TestFromCSV(t *testing.T) {
+	input := "Name,Age,city,Salary,Active,Extra\n" +
+		"Alice,30,NYC,75000,true,x\n" +
+		"\"Bob, Jr.\",25,LA,60000.5,false,y\n"
+
+	p, err := FromCSV[Person](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Person{
+		{"Alice", 30, "NYC", 75000, true},
+		{"Bob, Jr.", 25, "LA", 60000.5, false},
+	}
+	if !reflect.DeepEqual(p.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, p.Collect())
+	}
+	if !reflect.DeepEqual(p.Which(), []int{1, 2}) {
+		t.Errorf("Expected indices [1 2], got %v", p.Which())
+	}
+}
+
+func TestFromCSVTagsAndTypes(t *testing.T) {
+	input := "title;attendees;date;Length;Internal\n" +
+		"Launch;12;05/03/2024;1h30m;secret\n" +
+		"Retro;;06/03/2024;45m;secret\n"
+
+	p, err := FromCSV[CSVEvent](strings.NewReader(input),
+		CSVDelimiter(';'), CSVTimeLayouts("02/01/2006"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	events := p.Collect()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Attendee == nil || *events[0].Attendee != 12 {
+		t.Errorf("Expected 12 attendees, got %v", events[0].Attendee)
+	}
+	if events[1].Attendee != nil {
+		t.Errorf("Expected nil attendees for empty cell, got %v", *events[1].Attendee)
+	}
+	if !events[0].When.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected date %v", events[0].When)
+	}
+	if events[0].Length != 90*time.Minute {
+		t.Errorf("Expected 1h30m, got %v", events[0].Length)
+	}
+	if events[0].Internal != "" {
+		t.Errorf("Expected skipped field to stay empty, got %q", events[0].Internal)
+	}
+}
+
+func TestFromCSVHeaderMatching(t *testing.T) {
+	type contact struct {
+		FirstName string
+		LastName  string
+	}
+
+	p, err := FromCSV[contact](strings.NewReader("first_name,LASTNAME\nAda,Lovelace\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := p.Collect()[0]; got.FirstName != "" || got.LastName != "Lovelace" {
+		t.Errorf("Expected only the case-insensitive header to bind, got %+v", got)
+	}
+
+	p, err = FromCSV[contact](strings.NewReader("first_name,firstname\nA,B\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := p.Collect()[0]; got.FirstName != "B" {
+		t.Errorf("Expected firstname to fill FirstName, got %+v", got)
+	}
+}
+
+func TestFromCSVNoHeader(t *testing.T) {
+	input := "Alice,30,NYC,75000,true\nBob,25,LA,60000,false\n"
+
+	p, err := FromCSV[Person](strings.NewReader(input), CSVNoHeader())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Count() != 2 || p.Collect()[1].City != "LA" {
+		t.Errorf("Unexpected result %v", p.Collect())
+	}
+
+	named, err := FromCSV[Person](strings.NewReader("NYC,Alice\n"), CSVNoHeader("City", "Name"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := named.Collect()[0]; got.Name != "Alice" || got.City != "NYC" {
+		t.Errorf("Unexpected result %v", got)
+	}
+}
+
+func TestFromCSVErrors(t *testing.T) {
+	input := "Name,Age\nAlice,30\nBob,old\nCarol,28\n"
+
+	_, err := FromCSV[Person](strings.NewReader(input))
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) {
+		t.Fatalf("Expected RowError, got %v", err)
+	}
+	if rowErr.Line != 3 || rowErr.Field != "Age" || rowErr.Value != "old" {
+		t.Errorf("Unexpected error details %+v", rowErr)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Expected wrapped strconv.ErrSyntax, got %v", rowErr.Err)
+	}
+}
+
+func TestFromCSVSkipMalformed(t *testing.T) {
+	input := "Name,Age\nAlice,30\nBob,old\nCarol,28,extra\nDave,41\n"
+
+	var report ReadReport
+	p, err := FromCSV[Person](strings.NewReader(input), SkipMalformed(&report))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	names := Map(p, func(x Person) string { return x.Name }).Collect()
+	if !reflect.DeepEqual(names, []string{"Alice", "Dave"}) {
+		t.Errorf("Expected [Alice Dave], got %v", names)
+	}
+	if !reflect.DeepEqual(p.Which(), []int{1, 4}) {
+		t.Errorf("Expected record numbers [1 4], got %v", p.Which())
+	}
+
+	if len(report.Skipped) != 2 {
+		t.Fatalf("Expected 2 skipped rows, got %d", len(report.Skipped))
+	}
+	if report.Skipped[0].Line != 3 || report.Skipped[1].Line != 4 {
+		t.Errorf("Expected lines 3 and 4, got %d and %d", report.Skipped[0].Line, report.Skipped[1].Line)
+	}
+}
+
+func TestFromCSVEmptyAndNaN(t *testing.T) {
+	for _, input := range []string{"", "Name,Age,Salary\n"} {
+		p, err := FromCSV[Person](strings.NewReader(input))
+		if err != nil || p.Count() != 0 {
+			t.Errorf("Expected no rows for %q, got %v, %v", input, p, err)
+		}
+	}
+
+	p, err := FromCSV[Person](strings.NewReader("Name,Salary\nAlice,NaN\nBob,-Inf\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if people := p.Collect(); !math.IsNaN(people[0].Salary) || !math.IsInf(people[1].Salary, -1) {
+		t.Errorf("Expected NaN and -Inf salaries, got %v", people)
+	}
+
+	_, err = FromCSV[Person](strings.NewReader("Name,Age\nAlice,NaN\n"))
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) || rowErr.Field != "Age" {
+		t.Errorf("Expected a RowError for NaN in an int column, got %v", err)
+	}
+}
+
+func TestFromCSVMaps(t *testing.T) {
+	input := "b,a\n1,2\n"
+
+	p, err := FromCSV[map[string]any](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(p.FieldNames(), []string{"b", "a"}) {
+		t.Errorf("Expected header order, got %v", p.FieldNames())
+	}
+	if p.Collect()[0]["a"] != "2" {
+		t.Errorf("Expected string value, got %v", p.Collect()[0]["a"])
+	}
+}
+
+func TestFromCSVPointersAndUnsupported(t *testing.T) {
+	input := "Name,Age\nAlice,30\nBob,25\n"
+
+	p, err := FromCSV[*Person](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	people := p.Collect()
+	if len(people) != 2 || people[0] == nil || *people[0] != (Person{Name: "Alice", Age: 30}) || people[1].Name != "Bob" {
+		t.Errorf("Expected decoded pointers, got %v", people)
+	}
+
+	if _, err := FromCSV[int](strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), "cannot read rows into int") {
+		t.Errorf("Expected unsupported type error, got %v", err)
+	}
+	if _, err := FromCSV[map[string]string](strings.NewReader("")); err == nil {
+		t.Error("Expected unsupported type error for map[string]string")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	people := []Person{
+		{"Alice", 30, "New York, NY", 75000.5, true},
+		{"Bob", 25, "LA", 60000, false},
+	}
+
+	var buf strings.Builder
+	if err := From(people).WriteCSV(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "Name,Age,City,Salary,Active\n" +
+		"Alice,30,\"New York, NY\",75000.5,true\n" +
+		"Bob,25,LA,60000,false\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	back, err := FromCSV[Person](strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Unexpected error reading back: %v", err)
+	}
+	if !reflect.DeepEqual(back.Collect(), people) {
+		t.Errorf("Round trip mismatch: %v", back.Collect())
+	}
+}
+
+func TestWriteCSVOptions(t *testing.T) {
+	n := 12
+	events := []CSVEvent{
+		{"Launch", &n, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), time.Hour, "secret"},
+		{"Retro", nil, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), 0, "secret"},
+	}
+
+	var buf strings.Builder
+	err := From(events).WriteCSV(&buf,
+		CSVDelimiter('\t'), CSVTimeLayouts("2006-01-02"), CSVNull("NA"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "title\tattendees\tdate\tLength\n" +
+		"Launch\t12\t2024-03-05\t1h0m0s\n" +
+		"Retro\tNA\t2024-03-06\t0s\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	back, err := FromCSV[CSVEvent](strings.NewReader(buf.String()),
+		CSVDelimiter('\t'), CSVTimeLayouts("2006-01-02"), CSVNull("NA"))
+	if err != nil {
+		t.Fatalf("Unexpected error reading back: %v", err)
+	}
+	if got := back.Collect()[1]; got.Attendee != nil || got.Title != "Retro" {
+		t.Errorf("Unexpected round trip %+v", got)
+	}
+}
+
+func TestSelectionWriteCSV(t *testing.T) {
+	people := []Person{{"Alice", 30, "NYC", 75000.456, true}}
+
+	var buf strings.Builder
+	err := From(people).Select("Salary", "Name").WriteCSV(&buf, CSVFloatPrecision(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "Salary,Name\n75000.46,Alice\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWriteCSVMaps(t *testing.T) {
+	rows := []map[string]any{{"b": 1, "a": nil}, {"c": "x"}}
+
+	var buf strings.Builder
+	if err := From(rows).WriteCSV(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "a,b,c\n,1,\n,,x\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWriteCSVEmptyAndNaN(t *testing.T) {
+	var buf strings.Builder
+	if err := From([]Person{}).WriteCSV(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "Name,Age,City,Salary,Active\n" {
+		t.Errorf("Expected only the header, got %q", buf.String())
+	}
+
+	people := []Person{{"Alice", 30, "NYC", math.NaN(), true}, {"Bob", 25, "LA", math.Inf(1), false}}
+	buf.Reset()
+	if err := From(people).WriteCSV(&buf, CSVFloatPrecision(2)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "Name,Age,City,Salary,Active\nAlice,30,NYC,NaN,true\nBob,25,LA,+Inf,false\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	back, err := FromCSV[Person](strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Unexpected error reading back: %v", err)
+	}
+	if got := back.Collect(); !math.IsNaN(got[0].Salary) || !math.IsInf(got[1].Salary, 1) {
+		t.Errorf("Expected non-finite salaries to round trip, got %v", got)
+	}
+}
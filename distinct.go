@@ -0,0 +1,121 @@
+package plygo
+
+import "math"
+
+// KeepPolicy decides which row of a set of duplicates DistinctKeep keeps.
+type KeepPolicy struct {
+	mode  int
+	field string
+}
+
+const (
+	keepFirst = iota
+	keepLast
+	keepMax
+	keepMin
+)
+
+var (
+	KeepFirst = KeepPolicy{mode: keepFirst}
+	KeepLast  = KeepPolicy{mode: keepLast}
+)
+
+// KeepMax keeps the duplicate with the highest value of field. Pointers are
+// followed, and nil and NaN values only win when every duplicate has one.
+func KeepMax(field string) KeepPolicy {
+	return KeepPolicy{mode: keepMax, field: field}
+}
+
+// KeepMin keeps the duplicate with the lowest value of field, following
+// pointers and passing over nil and NaN values like KeepMax.
+func KeepMin(field string) KeepPolicy {
+	return KeepPolicy{mode: keepMin, field: field}
+}
+
+// DistinctBy keeps the first row for each combination of fields. With no
+// fields, whole rows are compared.
+func (p *Pipeline[T]) DistinctBy(fields ...string) *Pipeline[T] {
+	return p.DistinctKeep(KeepFirst, fields...)
+}
+
+// DistinctAll removes rows that are equal in every field.
+func (p *Pipeline[T]) DistinctAll() *Pipeline[T] {
+	return p.DistinctKeep(KeepFirst)
+}
+
+// DistinctKeep removes duplicates by fields (or whole rows when none are
+// given), keeping the row chosen by policy. Kept rows stay in their
+// original order.
+func (p *Pipeline[T]) DistinctKeep(policy KeepPolicy, fields ...string) *Pipeline[T] {
+	chosen := make(map[string]int)
+	keys := make([]string, len(p.data))
+
+	for i, item := range p.data {
+		key := setKey(item, fields)
+		keys[i] = key
+
+		current, ok := chosen[key]
+		if !ok {
+			chosen[key] = i
+			continue
+		}
+
+		switch policy.mode {
+		case keepLast:
+			chosen[key] = i
+		case keepMax, keepMin:
+			val, ok := keepValue(item, policy.field)
+			if !ok {
+				continue
+			}
+			best, ok := keepValue(p.data[current], policy.field)
+			c := compareValues(val, best)
+			if !ok || (policy.mode == keepMax && c > 0) || (policy.mode == keepMin && c < 0) {
+				chosen[key] = i
+			}
+		}
+	}
+
+	positions := make([]int, 0, len(chosen))
+	for i, key := range keys {
+		if chosen[key] == i {
+			positions = append(positions, i)
+		}
+	}
+	return p.subset(positions)
+}
+
+// Duplicates returns every row whose fields (or whole row when none are
+// given) occur more than once.
+func (p *Pipeline[T]) Duplicates(fields ...string) *Pipeline[T] {
+	counts := make(map[string]int)
+	keys := make([]string, len(p.data))
+
+	for i, item := range p.data {
+		keys[i] = setKey(item, fields)
+		counts[keys[i]]++
+	}
+
+	positions := make([]int, 0)
+	for i, key := range keys {
+		if counts[key] > 1 {
+			positions = append(positions, i)
+		}
+	}
+	return p.subset(positions)
+}
+
+// keepValue returns the value of field compared by KeepMax and KeepMin, and
+// false when it is missing: nil, a nil pointer or NaN.
+func keepValue(item any, field string) (any, bool) {
+	v := derefValue(getFieldValue(item, field))
+	switch f := v.(type) {
+	case nil:
+		return nil, false
+	case float64:
+		return v, !math.IsNaN(f)
+	case float32:
+		return v, !math.IsNaN(float64(f))
+	}
+	return v, true
+}
@@ -0,0 +1,123 @@
+package plygo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// distinctPeople is samplePeople with Alice repeated as an exact duplicate.
+func distinctPeople() []Person {
+	people := samplePeople()
+	return append(people, people[0])
+}
+
+func TestDistinctBy(t *testing.T) {
+	result := From(distinctPeople()).DistinctBy("City", "Active")
+
+	if !reflect.DeepEqual(result.Which(), []int{1, 2, 3, 4}) {
+		t.Errorf("Expected [1 2 3 4], got %v", result.Which())
+	}
+}
+
+func TestDistinctAll(t *testing.T) {
+	result := From(distinctPeople()).DistinctAll()
+
+	if !reflect.DeepEqual(result.Which(), []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("Expected exact duplicate row 8 to be removed, got %v", result.Which())
+	}
+}
+
+func TestDistinct_SliceValues(t *testing.T) {
+	tagged := From([]map[string]any{
+		{"Tags": []string{"new"}},
+		{"Tags": []string{"vip"}},
+		{"Tags": []string{"new"}},
+	})
+
+	if got := tagged.Distinct("Tags").Count(); got != 2 {
+		t.Errorf("Expected 2 distinct tag lists, got %d", got)
+	}
+	if counts := tagged.GroupBy("Tags").Count(); len(counts) != 2 {
+		t.Errorf("Expected 2 groups of tags, got %v", counts)
+	}
+}
+
+func TestDistinctKeep(t *testing.T) {
+	people := From(distinctPeople())
+
+	last := people.DistinctKeep(KeepLast, "City")
+	if !reflect.DeepEqual(last.Which(), []int{5, 7, 8}) {
+		t.Errorf("KeepLast: expected [5 7 8], got %v", last.Which())
+	}
+
+	best := people.DistinctKeep(KeepMax("Salary"), "City")
+	if !reflect.DeepEqual(best.Which(), []int{4, 5, 6}) {
+		t.Errorf("KeepMax: expected [4 5 6], got %v", best.Which())
+	}
+
+	worst := people.DistinctKeep(KeepMin("Salary"), "City")
+	if !reflect.DeepEqual(worst.Which(), []int{1, 2, 7}) {
+		t.Errorf("KeepMin: expected [1 2 7], got %v", worst.Which())
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	people := From(distinctPeople())
+
+	byAge := people.Duplicates("Age")
+	if !reflect.DeepEqual(byAge.Which(), []int{1, 2, 7, 8}) {
+		t.Errorf("Expected [1 2 7 8], got %v", byAge.Which())
+	}
+
+	exact := people.Duplicates()
+	if !reflect.DeepEqual(exact.Which(), []int{1, 8}) {
+		t.Errorf("Expected [1 8], got %v", exact.Which())
+	}
+}
+
+func TestDistinct_NaNAndEmpty(t *testing.T) {
+	people := samplePeople()
+	people[0].Salary = math.NaN()
+	people[2].Salary = math.NaN()
+
+	if got := From(people).Distinct("Salary").Which(); !reflect.DeepEqual(got, []int{1, 2, 4, 5, 6, 7}) {
+		t.Errorf("Expected NaN salaries to be duplicates, got %v", got)
+	}
+	if got := From(people).Duplicates("Salary").Which(); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("Expected [1 3], got %v", got)
+	}
+	if got := From(people).DistinctKeep(KeepMax("Salary"), "City").Which(); !reflect.DeepEqual(got, []int{4, 5, 6}) {
+		t.Errorf("Expected NaN never to be the maximum, got %v", got)
+	}
+	if got := From(people).DistinctKeep(KeepMin("Salary"), "City").Which(); !reflect.DeepEqual(got, []int{2, 6, 7}) {
+		t.Errorf("Expected NaN never to be the minimum, got %v", got)
+	}
+
+	none := From([]Person{})
+	if none.DistinctAll().Count() != 0 || none.DistinctKeep(KeepLast, "City").Count() != 0 || none.Duplicates().Count() != 0 {
+		t.Error("Expected empty results from empty input")
+	}
+}
+
+func TestDistinctKeep_PointerFields(t *testing.T) {
+	type bid struct {
+		Item   string
+		Amount *int
+	}
+	amount := func(v int) *int { return &v }
+	bids := From([]bid{
+		{"lamp", nil},
+		{"lamp", amount(5)},
+		{"lamp", amount(12)},
+		{"desk", amount(40)},
+		{"lamp", amount(3)},
+	})
+
+	if got := bids.DistinctKeep(KeepMax("Amount"), "Item").Which(); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Errorf("Expected the highest pointee to win, got %v", got)
+	}
+	if got := bids.DistinctKeep(KeepMin("Amount"), "Item").Which(); !reflect.DeepEqual(got, []int{4, 5}) {
+		t.Errorf("Expected the lowest pointee to win over nil, got %v", got)
+	}
+}
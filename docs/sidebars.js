@@ -16,6 +16,7 @@ module.exports = {
         'tutorial-basics/selecting',
         'tutorial-basics/sorting',
         'tutorial-basics/grouping',
+        'tutorial-basics/joins',
         'tutorial-basics/transformation',
         'tutorial-basics/positions',
         'tutorial-basics/utilities',
@@ -39,6 +40,10 @@ module.exports = {
       label: '4️⃣ Extras',
       items: [
         'extras/csv-loading',
+        'extras/json',
+        'extras/database',
+        'extras/columnar',
+        'extras/excel',
         'extras/real-world-examples',
         'extras/faq',
       ],
@@ -0,0 +1,273 @@
+package plygo
+
+import "encoding/binary"
+
+// A minimal FlatBuffers encoder and decoder, just enough for the Arrow IPC
+// metadata (Message, Schema and Footer tables). Objects are laid out front
+// to back: each table is preceded by its vtable and followed by its
+// children, so every uoffset points forward as the format requires.
+
+// formatError reports malformed binary input. Decoders panic with it on
+// out-of-range reads and turn it back into an error with recoverFormat.
+type formatError string
+
+func (e formatError) Error() string { return string(e) }
+
+const errFlatBuffer = formatError("malformed flatbuffer")
+
+type fbObject interface {
+	writeTo(b *fbBuilder) int
+}
+
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) pad(align int) {
+	for len(b.buf)%align != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *fbBuilder) grow(n int) int {
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, n)...)
+	return pos
+}
+
+func (b *fbBuilder) patch(at, target int) {
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(target-at))
+}
+
+// fbFinish serializes root and returns the buffer padded to 8 bytes.
+func fbFinish(root fbObject) []byte {
+	b := &fbBuilder{buf: make([]byte, 4, 256)}
+	b.patch(0, root.writeTo(b))
+	b.pad(8)
+	return b.buf
+}
+
+// fbField is one slot of a table: a scalar of the given width, or a child
+// object referenced by offset.
+type fbField struct {
+	width int
+	value uint64
+	child fbObject
+}
+
+func fbScalar(width int, v uint64) *fbField { return &fbField{width: width, value: v} }
+func fbBool(v bool) *fbField {
+	if v {
+		return fbScalar(1, 1)
+	}
+	return fbScalar(1, 0)
+}
+func fbChild(obj fbObject) *fbField { return &fbField{width: 4, child: obj} }
+
+// fbTable lists a table's fields by id; nil fields are absent.
+type fbTable []*fbField
+
+func (t fbTable) writeTo(b *fbBuilder) int {
+	offsets := make([]int, len(t))
+	size := 4
+	for _, f := range t {
+		if f != nil && f.width == 8 {
+			size = 8
+		}
+	}
+	for _, width := range []int{8, 4, 2, 1} {
+		for i, f := range t {
+			if f != nil && f.width == width {
+				offsets[i] = size
+				size += width
+			}
+		}
+	}
+
+	vtSize := 4 + 2*len(t)
+	for (len(b.buf)+vtSize)%8 != 0 {
+		b.buf = append(b.buf, 0)
+	}
+	vtPos := b.grow(vtSize)
+	binary.LittleEndian.PutUint16(b.buf[vtPos:], uint16(vtSize))
+	binary.LittleEndian.PutUint16(b.buf[vtPos+2:], uint16(size))
+	for i, off := range offsets {
+		binary.LittleEndian.PutUint16(b.buf[vtPos+4+2*i:], uint16(off))
+	}
+
+	pos := b.grow(size)
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(pos-vtPos))
+	for i, f := range t {
+		if f == nil || f.child != nil {
+			continue
+		}
+		at := b.buf[pos+offsets[i]:]
+		switch f.width {
+		case 1:
+			at[0] = byte(f.value)
+		case 2:
+			binary.LittleEndian.PutUint16(at, uint16(f.value))
+		case 4:
+			binary.LittleEndian.PutUint32(at, uint32(f.value))
+		case 8:
+			binary.LittleEndian.PutUint64(at, f.value)
+		}
+	}
+
+	for i, f := range t {
+		if f != nil && f.child != nil {
+			b.patch(pos+offsets[i], f.child.writeTo(b))
+		}
+	}
+	return pos
+}
+
+type fbString string
+
+func (s fbString) writeTo(b *fbBuilder) int {
+	b.pad(4)
+	pos := b.grow(4 + len(s) + 1)
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(len(s)))
+	copy(b.buf[pos+4:], s)
+	return pos
+}
+
+// fbVector is a vector of tables or strings.
+type fbVector []fbObject
+
+func (v fbVector) writeTo(b *fbBuilder) int {
+	b.pad(4)
+	pos := b.grow(4 + 4*len(v))
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(len(v)))
+	for i, obj := range v {
+		b.patch(pos+4+4*i, obj.writeTo(b))
+	}
+	return pos
+}
+
+// fbStructs is a vector of n inline structs with 8-byte alignment.
+type fbStructs struct {
+	n    int
+	data []byte
+}
+
+func (v fbStructs) writeTo(b *fbBuilder) int {
+	for (len(b.buf)+4)%8 != 0 {
+		b.buf = append(b.buf, 0)
+	}
+	pos := b.grow(4 + len(v.data))
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(v.n))
+	copy(b.buf[pos+4:], v.data)
+	return pos
+}
+
+// fbRef points at a table inside a buffer being decoded. Out-of-range reads
+// panic with errFlatBuffer.
+type fbRef struct {
+	buf []byte
+	pos int
+}
+
+func fbRoot(buf []byte) fbRef {
+	return fbRef{buf: buf, pos: int(fbU32(buf, 0))}
+}
+
+func fbBytes(buf []byte, pos, n int) []byte {
+	if pos < 0 || n < 0 || pos > len(buf) || n > len(buf)-pos {
+		panic(errFlatBuffer)
+	}
+	return buf[pos : pos+n]
+}
+
+func fbU32(buf []byte, pos int) uint32 {
+	return binary.LittleEndian.Uint32(fbBytes(buf, pos, 4))
+}
+
+func recoverFormat(err *error) {
+	if r := recover(); r != nil {
+		e, ok := r.(formatError)
+		if !ok {
+			panic(r)
+		}
+		*err = e
+	}
+}
+
+// field returns the absolute position of field id, or 0 when absent.
+func (t fbRef) field(id int) int {
+	vt := t.pos - int(int32(fbU32(t.buf, t.pos)))
+	vtSize := int(binary.LittleEndian.Uint16(fbBytes(t.buf, vt, 2)))
+	if 4+2*id >= vtSize {
+		return 0
+	}
+	off := int(binary.LittleEndian.Uint16(fbBytes(t.buf, vt+4+2*id, 2)))
+	if off == 0 {
+		return 0
+	}
+	return t.pos + off
+}
+
+func (t fbRef) scalar(id, width int) uint64 {
+	pos := t.field(id)
+	if pos == 0 {
+		return 0
+	}
+	b := fbBytes(t.buf, pos, width)
+	switch width {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(b))
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(b))
+	default:
+		return binary.LittleEndian.Uint64(b)
+	}
+}
+
+func (t fbRef) target(id int) int {
+	pos := t.field(id)
+	if pos == 0 {
+		return 0
+	}
+	return pos + int(fbU32(t.buf, pos))
+}
+
+func (t fbRef) table(id int) (fbRef, bool) {
+	pos := t.target(id)
+	return fbRef{buf: t.buf, pos: pos}, pos != 0
+}
+
+func (t fbRef) str(id int) string {
+	pos := t.target(id)
+	if pos == 0 {
+		return ""
+	}
+	return string(fbBytes(t.buf, pos+4, int(fbU32(t.buf, pos))))
+}
+
+// vector returns the position of the first element and the length.
+func (t fbRef) vector(id int) (int, int) {
+	pos := t.target(id)
+	if pos == 0 {
+		return 0, 0
+	}
+	return pos + 4, int(fbU32(t.buf, pos))
+}
+
+func (t fbRef) tables(id int) []fbRef {
+	start, n := t.vector(id)
+	fbBytes(t.buf, start, 4*n)
+	result := make([]fbRef, n)
+	for i := range result {
+		at := start + 4*i
+		result[i] = fbRef{buf: t.buf, pos: at + int(fbU32(t.buf, at))}
+	}
+	return result
+}
+
+// structs returns the raw bytes of a vector of n structs of the given size.
+func (t fbRef) structs(id, size int) ([]byte, int) {
+	start, n := t.vector(id)
+	return fbBytes(t.buf, start, n*size), n
+}
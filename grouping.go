@@ -0,0 +1,105 @@
+package plygo
+
+import "sort"
+
+// Group is a single group produced by a Grouping: its key and the rows that
+// belong to it, with their original indices preserved.
+type Group[T any] struct {
+	Key  any
+	Rows *Pipeline[T]
+}
+
+// Groups returns every group in order of first appearance.
+func (g *Grouping[T]) Groups() []Group[T] {
+	keys, members := g.partition()
+
+	result := make([]Group[T], len(keys))
+	for i, key := range keys {
+		result[i] = Group[T]{Key: key, Rows: g.pipeline.subset(members[key])}
+	}
+	return result
+}
+
+// Apply calls fn once per group and collects the results by group key.
+func (g *Grouping[T]) Apply(fn func(*Pipeline[T]) any) map[any]any {
+	return ApplyGroups(g, fn)
+}
+
+// ApplyGroups is the typed form of Grouping.Apply.
+func ApplyGroups[T, R any](g *Grouping[T], fn func(*Pipeline[T]) R) map[any]R {
+	result := make(map[any]R)
+	for _, group := range g.Groups() {
+		result[group.Key] = fn(group.Rows)
+	}
+	return result
+}
+
+// Filter keeps only the groups for which keep returns true, like SQL HAVING.
+// The returned Grouping can be aggregated or iterated as usual.
+func (g *Grouping[T]) Filter(keep func(*Pipeline[T]) bool) *Grouping[T] {
+	keys, members := g.partition()
+
+	positions := make([]int, 0)
+	for _, key := range keys {
+		if keep(g.pipeline.subset(members[key])) {
+			positions = append(positions, members[key]...)
+		}
+	}
+	sort.Ints(positions)
+
+	return &Grouping[T]{
+		pipeline: g.pipeline.subset(positions),
+		field:    g.field,
+		fields:   g.fields,
+		bucket:   g.bucket,
+	}
+}
+
+// TopNPerGroup returns the n rows with the highest value of field in each
+// group. Groups appear in order of first appearance.
+func (g *Grouping[T]) TopNPerGroup(field string, n int) *Pipeline[T] {
+	keys, members := g.partition()
+
+	positions := make([]int, 0)
+	for _, key := range keys {
+		rows := append([]int(nil), members[key]...)
+		sort.SliceStable(rows, func(i, j int) bool {
+			vi := getFieldValue(g.pipeline.data[rows[i]], field)
+			vj := getFieldValue(g.pipeline.data[rows[j]], field)
+			return compareValues(vi, vj) > 0
+		})
+		if n < len(rows) {
+			rows = rows[:max(n, 0)]
+		}
+		positions = append(positions, rows...)
+	}
+
+	return g.pipeline.subset(positions)
+}
+
+func (g *Grouping[T]) keyOf(item T) any {
+	if len(g.fields) > 0 {
+		return setKey(item, g.fields)
+	}
+
+	val := getFieldValue(item, g.field)
+	if g.bucket != nil && val != nil {
+		return g.bucket(val)
+	}
+	return valueKey(val)
+}
+
+func (g *Grouping[T]) partition() ([]any, map[any][]int) {
+	keys := make([]any, 0)
+	members := make(map[any][]int)
+
+	for i, item := range g.pipeline.data {
+		key := g.keyOf(item)
+		if _, ok := members[key]; !ok {
+			keys = append(keys, key)
+		}
+		members[key] = append(members[key], i)
+	}
+
+	return keys, members
+}
@@ -0,0 +1,171 @@
+package plygo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroups_OrderAndIndices(t *testing.T) {
+	groups := From(samplePeople()).GroupBy("City").Groups()
+
+	if len(groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(groups))
+	}
+
+	keys := []any{groups[0].Key, groups[1].Key, groups[2].Key}
+	if !reflect.DeepEqual(keys, []any{"NYC", "LA", "Chicago"}) {
+		t.Errorf("Expected groups in order of first appearance, got %v", keys)
+	}
+
+	if !reflect.DeepEqual(groups[0].Rows.Which(), []int{1, 3, 6}) {
+		t.Errorf("Expected NYC rows [1 3 6], got %v", groups[0].Rows.Which())
+	}
+	if !reflect.DeepEqual(groups[1].Rows.Which(), []int{2, 5}) {
+		t.Errorf("Expected LA rows [2 5], got %v", groups[1].Rows.Which())
+	}
+}
+
+func TestGroups_AfterWhere(t *testing.T) {
+	groups := From(samplePeople()).
+		Where("Salary").GreaterThan(70000).
+		GroupBy("City").
+		Groups()
+
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(groups))
+	}
+	if !reflect.DeepEqual(groups[1].Rows.Which(), []int{5}) {
+		t.Errorf("Expected LA rows [5], got %v", groups[1].Rows.Which())
+	}
+}
+
+func TestGroupingApply(t *testing.T) {
+	result := From(samplePeople()).GroupBy("City").Apply(func(p *Pipeline[Person]) any {
+		first, _ := p.First()
+		return first.Name
+	})
+
+	if result["NYC"] != "Alice" || result["LA"] != "Bob" || result["Chicago"] != "Diana" {
+		t.Errorf("Unexpected Apply result: %v", result)
+	}
+
+	counts := ApplyGroups(From(samplePeople()).GroupBy("City"), func(p *Pipeline[Person]) int {
+		return p.Count()
+	})
+	if counts["NYC"] != 3 {
+		t.Errorf("Expected 3 people in NYC, got %d", counts["NYC"])
+	}
+}
+
+func TestGroupingFilter(t *testing.T) {
+	grouping := From(samplePeople()).
+		GroupBy("City").
+		Filter(func(p *Pipeline[Person]) bool { return p.Count() < 3 })
+
+	counts := grouping.Count()
+	if len(counts) != 2 {
+		t.Errorf("Expected 2 groups after Filter, got %d", len(counts))
+	}
+	if _, ok := counts["NYC"]; ok {
+		t.Error("NYC should have been filtered out")
+	}
+
+	avg := grouping.Avg("Salary")
+	if avg["LA"] != 72500 {
+		t.Errorf("Expected LA average 72500, got %f", avg["LA"])
+	}
+}
+
+func TestTopNPerGroup(t *testing.T) {
+	top := From(samplePeople()).GroupBy("City").TopNPerGroup("Salary", 2)
+
+	names := make([]string, 0)
+	for _, e := range top.Collect() {
+		names = append(names, e.Name)
+	}
+
+	expected := []string{"Frank", "Charlie", "Eve", "Bob", "Diana", "Grace"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+	if !reflect.DeepEqual(top.Which(), []int{6, 3, 5, 2, 4, 7}) {
+		t.Errorf("Expected original indices [6 3 5 2 4 7], got %v", top.Which())
+	}
+}
+
+func TestGroupingMap_Aggregates(t *testing.T) {
+	selected := From(samplePeople()).Select("City", "Salary")
+
+	typed := From(samplePeople()).GroupBy("City")
+	mapped := selected.GroupBy("City")
+
+	if !reflect.DeepEqual(typed.Count(), mapped.Count()) {
+		t.Errorf("Count differs: %v vs %v", typed.Count(), mapped.Count())
+	}
+	if !reflect.DeepEqual(typed.Sum("Salary"), mapped.Sum("Salary")) {
+		t.Errorf("Sum differs: %v vs %v", typed.Sum("Salary"), mapped.Sum("Salary"))
+	}
+	if !reflect.DeepEqual(typed.Avg("Salary"), mapped.Avg("Salary")) {
+		t.Errorf("Avg differs: %v vs %v", typed.Avg("Salary"), mapped.Avg("Salary"))
+	}
+	if !reflect.DeepEqual(typed.Min("Salary"), mapped.Min("Salary")) {
+		t.Errorf("Min differs: %v vs %v", typed.Min("Salary"), mapped.Min("Salary"))
+	}
+	if !reflect.DeepEqual(typed.Max("Salary"), mapped.Max("Salary")) {
+		t.Errorf("Max differs: %v vs %v", typed.Max("Salary"), mapped.Max("Salary"))
+	}
+}
+
+func TestGroupingMap_Groups(t *testing.T) {
+	groups := From(samplePeople()).
+		Select("Name", "City").
+		GroupBy("City").
+		Groups()
+
+	if len(groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(groups))
+	}
+	if !reflect.DeepEqual(groups[1].Rows.Which(), []int{2, 5}) {
+		t.Errorf("Expected LA rows [2 5], got %v", groups[1].Rows.Which())
+	}
+
+	top := From(samplePeople()).
+		Select("Name", "City", "Salary").
+		GroupBy("City").
+		TopNPerGroup("Salary", 1).
+		Collect()
+	if len(top) != 3 || top[0]["Name"] != "Frank" {
+		t.Errorf("Unexpected top rows: %v", top)
+	}
+}
+
+func TestGroups_EmptyInput(t *testing.T) {
+	grouping := From([]Person{}).GroupBy("City")
+	if groups := grouping.Groups(); len(groups) != 0 {
+		t.Errorf("Expected no groups, got %d", len(groups))
+	}
+	if top := grouping.TopNPerGroup("Salary", 2); top.Count() != 0 {
+		t.Errorf("Expected no rows, got %d", top.Count())
+	}
+	if applied := grouping.Apply(func(p *Pipeline[Person]) any { return p.Count() }); len(applied) != 0 {
+		t.Errorf("Expected an empty Apply result, got %v", applied)
+	}
+}
+
+func TestGroupingMap_FromMapPipeline(t *testing.T) {
+	rows := []map[string]any{
+		{"Region": "North", "Amount": 10},
+		{"Region": "South", "Amount": 5},
+		{"Region": "North", "Amount": 7},
+	}
+
+	highest := From(rows).Select("Region", "Amount").GroupBy("Region").Max("Amount")
+	if highest["North"] != 10 || highest["South"] != 5 {
+		t.Errorf("Unexpected Max result: %v", highest)
+	}
+
+	count := From(rows).Select("Region", "Amount").Where("Amount").GreaterThan(6).GroupBy("Region").Count()
+	if count["North"] != 2 || count["South"] != 0 {
+		t.Errorf("Unexpected Count result: %v", count)
+	}
+}
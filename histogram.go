@@ -0,0 +1,179 @@
+package plygo
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValueCount is one row of a frequency table produced by ValueCounts.
+type ValueCount struct {
+	Value   any
+	Count   int
+	Percent float64
+}
+
+// Histogram holds equal-width bin edges and the number of values in each
+// bin. Edges has one more element than Counts; the last bin includes its
+// upper edge.
+type Histogram struct {
+	Edges  []float64
+	Counts []int
+}
+
+// ValueCounts returns how often each value of field occurs, most frequent
+// first. Ties are ordered by value.
+func (p *Pipeline[T]) ValueCounts(field string) *Pipeline[ValueCount] {
+	counts := make(map[any]int)
+	values := make(map[any]any)
+	keys := make([]any, 0)
+
+	for _, item := range p.data {
+		val := getFieldValue(item, field)
+		key := valueKey(val)
+		if _, ok := counts[key]; !ok {
+			keys = append(keys, key)
+			values[key] = val
+		}
+		counts[key]++
+	}
+
+	result := make([]ValueCount, len(keys))
+	for i, key := range keys {
+		result[i] = ValueCount{
+			Value:   values[key],
+			Count:   counts[key],
+			Percent: float64(counts[key]) * 100 / float64(len(p.data)),
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return compareValues(result[i].Value, result[j].Value) < 0
+	})
+
+	return From(result)
+}
+
+// Histogram splits the range of a numeric field into bins of equal width
+// and counts the values in each. Pointers are followed; nil, NaN, infinite
+// and non-numeric values are ignored, so a non-numeric field gives an empty
+// histogram.
+func (p *Pipeline[T]) Histogram(field string, bins int) Histogram {
+	values := make([]float64, 0, len(p.data))
+	for _, item := range p.data {
+		val := derefValue(getFieldValue(item, field))
+		if val == nil {
+			continue
+		}
+
+		var f float64
+		switch rv := reflect.ValueOf(val); {
+		case rv.CanInt():
+			f = float64(rv.Int())
+		case rv.CanUint():
+			f = float64(rv.Uint())
+		case rv.CanFloat():
+			f = rv.Float()
+		default:
+			continue
+		}
+		if !math.IsNaN(f) && !math.IsInf(f, 0) {
+			values = append(values, f)
+		}
+	}
+
+	if len(values) == 0 || bins <= 0 {
+		return Histogram{Edges: []float64{}, Counts: []int{}}
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if lo == hi {
+		lo, hi = lo-0.5, hi+0.5
+	}
+
+	width := (hi - lo) / float64(bins)
+	edges := make([]float64, bins+1)
+	for i := range edges {
+		edges[i] = lo + float64(i)*width
+	}
+	edges[bins] = hi
+
+	counts := make([]int, bins)
+	for _, v := range values {
+		bin := int((v - lo) / width)
+		bin = min(max(bin, 0), bins-1)
+		counts[bin]++
+	}
+
+	return Histogram{Edges: edges, Counts: counts}
+}
+
+// ShowHistogram draws a histogram as a table with a horizontal bar per bin,
+// using the same styles and options as Show.
+func ShowHistogram(h Histogram, options ...ShowOption) {
+	config := defaultShowConfig()
+	for _, opt := range options {
+		opt(config)
+	}
+
+	if len(h.Counts) == 0 {
+		printNote(config, "Empty histogram")
+		return
+	}
+
+	style := getTableStyle(config.style)
+	bar := "█"
+	if config.style == "simple" || config.style == "markdown" {
+		bar = "#"
+	}
+
+	largest, total := 0, 0
+	for _, c := range h.Counts {
+		largest = max(largest, c)
+		total += c
+	}
+
+	headers := []string{"Bin", "Count", "Distribution"}
+	rows := make([][]string, len(h.Counts))
+	for i, c := range h.Counts {
+		label := fmt.Sprintf("[%s, %s)", formatValue(h.Edges[i], config), formatValue(h.Edges[i+1], config))
+		if i == len(h.Counts)-1 {
+			label = strings.TrimSuffix(label, ")") + "]"
+		}
+
+		length := 0
+		if largest > 0 {
+			length = int(math.Round(float64(c) / float64(largest) * float64(config.maxColWidth)))
+		}
+		rows[i] = []string{label, fmt.Sprintf("%d", c), strings.Repeat(bar, length)}
+	}
+
+	if config.title != "" {
+		printTitle(config.title, config)
+	}
+
+	renderTable(headers, rows, style, config)
+	printNote(config, "[%d values in %d bins]", total, len(h.Counts))
+}
+
+// Fprint writes the ShowHistogram output to w.
+func (h Histogram) Fprint(w io.Writer, options ...ShowOption) {
+	ShowHistogram(h, withWriter(w, options)...)
+}
+
+// Render returns the ShowHistogram output as a string.
+func (h Histogram) Render(options ...ShowOption) string {
+	var b strings.Builder
+	h.Fprint(&b, options...)
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package plygo
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValueCounts(t *testing.T) {
+	counts := From(samplePeople()).ValueCounts("City").Collect()
+
+	expected := []ValueCount{
+		{"NYC", 3, 300.0 / 7},
+		{"Chicago", 2, 200.0 / 7},
+		{"LA", 2, 200.0 / 7},
+	}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, got %v", expected, counts)
+	}
+}
+
+func TestValueCounts_TiesOrderedByValue(t *testing.T) {
+	counts := From(samplePeople()).ValueCounts("Age").Collect()
+	if counts[0].Value != 25 || counts[0].Count != 2 || counts[1].Value != 28 {
+		t.Errorf("Expected 25 first, then 28, got %v", counts)
+	}
+}
+
+func TestValueCounts_NaNAndEmpty(t *testing.T) {
+	people := samplePeople()
+	people[0].Salary = math.NaN()
+	people[1].Salary = math.NaN()
+
+	counts := From(people).ValueCounts("Salary").Collect()
+	if len(counts) != 6 || !math.IsNaN(counts[0].Value.(float64)) || counts[0].Count != 2 {
+		t.Errorf("Expected the NaN salaries counted together, got %v", counts)
+	}
+
+	if empty := From([]Person{}).ValueCounts("City").Collect(); len(empty) != 0 {
+		t.Errorf("Expected no counts, got %v", empty)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	people := []Person{
+		{"A", 20, "", 0, true},
+		{"B", 22, "", 0, true},
+		{"C", 25, "", 0, true},
+		{"D", 31, "", 0, true},
+		{"E", 39, "", 0, true},
+		{"F", 40, "", 0, true},
+	}
+
+	h := From(people).Histogram("Age", 4)
+
+	if !reflect.DeepEqual(h.Edges, []float64{20, 25, 30, 35, 40}) {
+		t.Errorf("Unexpected edges: %v", h.Edges)
+	}
+	if !reflect.DeepEqual(h.Counts, []int{2, 1, 1, 2}) {
+		t.Errorf("Unexpected counts: %v", h.Counts)
+	}
+}
+
+func TestHistogram_Empty(t *testing.T) {
+	h := From([]Person{}).Histogram("Age", 5)
+	if len(h.Counts) != 0 || len(h.Edges) != 0 {
+		t.Errorf("Expected empty histogram, got %v", h)
+	}
+}
+
+func TestHistogram_NonFinite(t *testing.T) {
+	people := []Person{
+		{"A", 20, "", math.NaN(), true},
+		{"B", 22, "", 10, true},
+		{"C", 25, "", math.Inf(1), true},
+		{"D", 31, "", 30, true},
+		{"E", 39, "", math.Inf(-1), true},
+	}
+
+	h := From(people).Histogram("Salary", 2)
+	if !reflect.DeepEqual(h.Edges, []float64{10, 20, 30}) || !reflect.DeepEqual(h.Counts, []int{1, 1}) {
+		t.Errorf("Expected non-finite values to be skipped, got %v", h)
+	}
+
+	h = From(people[:1]).Histogram("Salary", 3)
+	if len(h.Counts) != 0 {
+		t.Errorf("Expected empty histogram for a lone NaN, got %v", h)
+	}
+}
+
+func TestHistogram_NonNumeric(t *testing.T) {
+	if h := From(samplePeople()).Histogram("City", 2); len(h.Edges) != 0 || len(h.Counts) != 0 {
+		t.Errorf("Expected empty histogram for a string field, got %v", h)
+	}
+
+	one, three := 1, 3
+	records := []map[string]any{
+		{"v": "a"}, {"v": &one}, {"v": 2.0}, {"v": &three}, {"v": (*int)(nil)}, {"v": true},
+	}
+	h := From(records).Histogram("v", 2)
+	if !reflect.DeepEqual(h.Edges, []float64{1, 2, 3}) || !reflect.DeepEqual(h.Counts, []int{1, 2}) {
+		t.Errorf("Expected only numbers and pointers to numbers to be counted, got %v", h)
+	}
+}
+
+func TestShowHistogram(t *testing.T) {
+	h := Histogram{Edges: []float64{0, 10, 20}, Counts: []int{4, 2}}
+
+	output := captureOutput(func() {
+		ShowHistogram(h, WithStyle("rounded"), WithMaxColWidth(8), WithFloatPrecision(0), WithTitle("Ages"))
+	})
+
+	if !strings.Contains(output, "Ages") {
+		t.Error("Output should contain title")
+	}
+	if !strings.Contains(output, "╭") {
+		t.Error("Output should use rounded style characters")
+	}
+	if !strings.Contains(output, "[0, 10)") || !strings.Contains(output, "[10, 20]") {
+		t.Error("Output should contain bin labels")
+	}
+	if !strings.Contains(output, strings.Repeat("█", 8)) || strings.Contains(output, strings.Repeat("█", 9)) {
+		t.Error("Largest bar should span the column width")
+	}
+	if !strings.Contains(output, "[6 values in 2 bins]") {
+		t.Error("Output should contain summary")
+	}
+	if rendered := h.Render(WithStyle("rounded"), WithMaxColWidth(8), WithFloatPrecision(0), WithTitle("Ages")); rendered != output {
+		t.Errorf("Render should match ShowHistogram output:\n%s\n%s", rendered, output)
+	}
+}
@@ -0,0 +1,87 @@
+package plygo
+
+import (
+	"fmt"
+	"html"
+	"io"
+)
+
+// renderHTMLTable writes headers and rows as an HTML table. The title becomes
+// the caption, numeric cells are right-aligned like the text styles do, and
+// the separator row inserted by truncateRows becomes a single spanning cell.
+// Every element carries a plygo-* class so pages can style the output.
+func renderHTMLTable(w io.Writer, headers []string, rows [][]string, config *ShowConfig) {
+	indexed := len(headers) > 0 && headers[0] == "#"
+
+	fmt.Fprintln(w, `<table class="plygo-table">`)
+	if config.title != "" {
+		fmt.Fprintf(w, "<caption class=\"plygo-title\">%s</caption>\n", html.EscapeString(config.title))
+	}
+
+	fmt.Fprintln(w, "<thead>")
+	fmt.Fprint(w, "<tr>")
+	for i, header := range headers {
+		class := "plygo-header"
+		if i == 0 && indexed {
+			class += " plygo-index"
+		}
+		fmt.Fprintf(w, "<th class=\"%s\" scope=\"col\">%s</th>", class, html.EscapeString(header))
+	}
+	fmt.Fprintln(w, "</tr>")
+	fmt.Fprintln(w, "</thead>")
+
+	fmt.Fprintln(w, "<tbody>")
+	for _, row := range rows {
+		if isSeparatorRow(row) {
+			fmt.Fprintf(w, "<tr class=\"plygo-ellipsis\"><td colspan=\"%d\">…</td></tr>\n", len(headers))
+			continue
+		}
+
+		fmt.Fprint(w, `<tr class="plygo-row">`)
+		for i := range headers {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			tag, class := "td", "plygo-cell"
+			if i == 0 && indexed {
+				tag, class = "th", "plygo-index"
+			}
+			attrs := fmt.Sprintf(" class=\"%s\"", class)
+			if isNumeric(cell) {
+				attrs = fmt.Sprintf(" class=\"%s plygo-num\" style=\"text-align: right\"", class)
+			}
+			if tag == "th" {
+				attrs += ` scope="row"`
+			}
+			fmt.Fprintf(w, "<%s%s>%s</%s>", tag, attrs, html.EscapeString(cell), tag)
+		}
+		fmt.Fprintln(w, "</tr>")
+	}
+	fmt.Fprintln(w, "</tbody>")
+	fmt.Fprintln(w, "</table>")
+}
+
+// isSeparatorRow reports whether row is the "..." row added by truncateRows.
+func isSeparatorRow(row []string) bool {
+	if len(row) == 0 {
+		return false
+	}
+	for _, cell := range row {
+		if cell != "..." {
+			return false
+		}
+	}
+	return true
+}
+
+// printNote writes a summary or status line, wrapped in a paragraph when the
+// output is HTML.
+func printNote(config *ShowConfig, format string, args ...any) {
+	text := fmt.Sprintf(format, args...)
+	if config.style != "html" {
+		fmt.Fprintln(config.out, text)
+		return
+	}
+	fmt.Fprintf(config.out, "<p class=\"plygo-note\">%s</p>\n", html.EscapeString(text))
+}
@@ -0,0 +1,109 @@
+package plygo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func htmlTestPeople() []ShowTestPerson {
+	return []ShowTestPerson{
+		{"Alice", 30, "NYC", 75000.50, true},
+		{"Bob <admin>", 25, "L&A", 60000.00, false},
+		{"Carol", 41, "SF", 98000.25, true},
+		{"Dave", 35, "NYC", 81000.00, true},
+	}
+}
+
+// checkWellFormed fails the test unless every top-level element in out
+// parses as XML.
+func checkWellFormed(t *testing.T, out string) {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader("<root>" + out + "</root>"))
+	dec.Entity = xml.HTMLEntity
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			return
+		} else if err != nil {
+			t.Fatalf("Output is not well-formed: %v\n%s", err, out)
+		}
+	}
+}
+
+func TestShow_StyleHTML(t *testing.T) {
+	output := From(htmlTestPeople()).Render(WithStyle("html"), WithTitle("Staff & Pay"))
+	checkWellFormed(t, output)
+
+	expected := []string{
+		`<table class="plygo-table">`,
+		`<caption class="plygo-title">Staff &amp; Pay</caption>`,
+		`<thead>`,
+		`<th class="plygo-header" scope="col">Name</th>`,
+		`<tbody>`,
+		`<td class="plygo-cell">Bob &lt;admin&gt;</td>`,
+		`<td class="plygo-cell">L&amp;A</td>`,
+		`<td class="plygo-cell plygo-num" style="text-align: right">75000.50</td>`,
+		`<td class="plygo-cell">true</td>`,
+		`<p class="plygo-note">[4 rows × 5 columns]</p>`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("Expected output to contain %s\n%s", s, output)
+		}
+	}
+	if strings.Contains(output, "+---") || strings.Contains(output, "        Staff") {
+		t.Error("HTML output should not contain text borders or a text title")
+	}
+}
+
+func TestShow_StyleHTMLTruncatedWithIndices(t *testing.T) {
+	output := From(htmlTestPeople()).
+		Where("Age").GreaterThan(26).
+		Render(WithStyle("html"), WithOriginalIndices(true), WithMaxRows(2))
+	checkWellFormed(t, output)
+
+	expected := []string{
+		`<th class="plygo-header plygo-index" scope="col">#</th>`,
+		`<th class="plygo-index plygo-num" style="text-align: right" scope="row">1</th>`,
+		`<th class="plygo-index plygo-num" style="text-align: right" scope="row">4</th>`,
+		`<tr class="plygo-ellipsis"><td colspan="6">…</td></tr>`,
+		`<p class="plygo-note">[3 rows × 6 columns] (showing 2 of 3)</p>`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("Expected output to contain %s\n%s", s, output)
+		}
+	}
+	if strings.Contains(output, `scope="row">3</th>`) {
+		t.Error("Truncated row should not be rendered")
+	}
+}
+
+func TestShow_StyleHTMLOtherShowables(t *testing.T) {
+	var buf bytes.Buffer
+	records := []map[string]any{{"name": "x<y", "score": 1.5}}
+	From(records).Fprint(&buf, WithStyle("html"))
+	checkWellFormed(t, buf.String())
+	if !strings.Contains(buf.String(), `<td class="plygo-cell">x&lt;y</td>`) {
+		t.Errorf("Expected escaped map cell, got %s", buf.String())
+	}
+
+	output := Histogram{Edges: []float64{0, 1, 2}, Counts: []int{3, 1}}.Render(WithStyle("html"))
+	checkWellFormed(t, output)
+	if !strings.Contains(output, `<p class="plygo-note">[4 values in 2 bins]</p>`) {
+		t.Errorf("Expected histogram summary note, got %s", output)
+	}
+
+	output = PositionIndex{Rows: []int{2, 5}}.Render(WithStyle("html"))
+	checkWellFormed(t, output)
+	if !strings.Contains(output, `<p class="plygo-note">Original Positions: [2 5]</p>`) {
+		t.Errorf("Expected positions note, got %s", output)
+	}
+
+	output = From([]ShowTestPerson{}).Render(WithStyle("html"))
+	if output != "<p class=\"plygo-note\">Empty dataset</p>\n" {
+		t.Errorf("Unexpected empty output %q", output)
+	}
+}
@@ -0,0 +1,308 @@
+package plygo
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JoinKeys lists the fields compared on each side of a join.
+type JoinKeys struct {
+	left, right []string
+}
+
+// On joins on fields that have the same names on both sides. Use Right to
+// give the right side different names.
+func On(fields ...string) JoinKeys {
+	return JoinKeys{left: fields, right: fields}
+}
+
+// Right sets the right-side field names, in the same order as On.
+func (k JoinKeys) Right(fields ...string) JoinKeys {
+	return JoinKeys{left: k.left, right: fields}
+}
+
+// Join returns one row per pair of left and right rows whose key fields are
+// equal. Rows keep the original index of their left row, so Which() still
+// refers to the left source. Nil keys never match.
+func Join[L, R, O any](left *Pipeline[L], right *Pipeline[R], leftKey, rightKey string, combine func(L, R) O) *Pipeline[O] {
+	return JoinOn(left, right, On(leftKey).Right(rightKey), combine)
+}
+
+// LeftJoin is like Join but keeps left rows without a match; combine
+// receives nil for the missing right side.
+func LeftJoin[L, R, O any](left *Pipeline[L], right *Pipeline[R], leftKey, rightKey string, combine func(L, *R) O) *Pipeline[O] {
+	return LeftJoinOn(left, right, On(leftKey).Right(rightKey), combine)
+}
+
+// RightJoin is like Join but keeps right rows without a match; combine
+// receives nil for the missing left side. Such rows have original index 0.
+func RightJoin[L, R, O any](left *Pipeline[L], right *Pipeline[R], leftKey, rightKey string, combine func(*L, R) O) *Pipeline[O] {
+	return RightJoinOn(left, right, On(leftKey).Right(rightKey), combine)
+}
+
+// FullJoin keeps unmatched rows from both sides. Unmatched right rows come
+// after all left rows and have original index 0.
+func FullJoin[L, R, O any](left *Pipeline[L], right *Pipeline[R], leftKey, rightKey string, combine func(*L, *R) O) *Pipeline[O] {
+	return FullJoinOn(left, right, On(leftKey).Right(rightKey), combine)
+}
+
+// JoinOn is Join on a composite key.
+func JoinOn[L, R, O any](left *Pipeline[L], right *Pipeline[R], keys JoinKeys, combine func(L, R) O) *Pipeline[O] {
+	pairs := hashJoin(left, right, keys.left, keys.right, false, false)
+	return buildJoin(left, right, pairs, func(l *L, r *R) O { return combine(*l, *r) })
+}
+
+// LeftJoinOn is LeftJoin on a composite key.
+func LeftJoinOn[L, R, O any](left *Pipeline[L], right *Pipeline[R], keys JoinKeys, combine func(L, *R) O) *Pipeline[O] {
+	pairs := hashJoin(left, right, keys.left, keys.right, true, false)
+	return buildJoin(left, right, pairs, func(l *L, r *R) O { return combine(*l, r) })
+}
+
+// RightJoinOn is RightJoin on a composite key.
+func RightJoinOn[L, R, O any](left *Pipeline[L], right *Pipeline[R], keys JoinKeys, combine func(*L, R) O) *Pipeline[O] {
+	pairs := hashJoin(left, right, keys.left, keys.right, false, true)
+	return buildJoin(left, right, pairs, func(l *L, r *R) O { return combine(l, *r) })
+}
+
+// FullJoinOn is FullJoin on a composite key.
+func FullJoinOn[L, R, O any](left *Pipeline[L], right *Pipeline[R], keys JoinKeys, combine func(*L, *R) O) *Pipeline[O] {
+	pairs := hashJoin(left, right, keys.left, keys.right, true, true)
+	return buildJoin(left, right, pairs, combine)
+}
+
+// RangeJoin matches each left row with the right rows whose key fields are
+// equal and whose [rightStart, rightEnd) range contains the left field. A
+// nil rightEnd means the range is open-ended. Pass On() to match on the
+// range alone.
+func RangeJoin[L, R, O any](left *Pipeline[L], right *Pipeline[R], keys JoinKeys, leftField, rightStart, rightEnd string, combine func(L, R) O) *Pipeline[O] {
+	index := make(map[any][]int)
+	for j, item := range right.data {
+		if getFieldValue(item, rightStart) == nil {
+			continue
+		}
+		if key, ok := joinKey(item, keys.right); ok {
+			index[key] = append(index[key], j)
+		}
+	}
+
+	pairs := make([]joinPair, 0, len(left.data))
+	for i, item := range left.data {
+		val := getFieldValue(item, leftField)
+		key, ok := joinKey(item, keys.left)
+		if val == nil || !ok {
+			continue
+		}
+
+		for _, j := range index[key] {
+			start := getFieldValue(right.data[j], rightStart)
+			end := getFieldValue(right.data[j], rightEnd)
+			if compareValues(start, val) <= 0 && (end == nil || compareValues(val, end) < 0) {
+				pairs = append(pairs, joinPair{left: i, right: j})
+			}
+		}
+	}
+
+	return buildJoin(left, right, pairs, func(l *L, r *R) O { return combine(*l, *r) })
+}
+
+// AsOfJoin matches each left row with the right row that has equal key
+// fields and the latest rightOn value not after leftOn, e.g. the price in
+// effect at the time of a trade. Every left row is kept; combine receives
+// nil when there is no earlier right row.
+func AsOfJoin[L, R, O any](left *Pipeline[L], right *Pipeline[R], keys JoinKeys, leftOn, rightOn string, combine func(L, *R) O) *Pipeline[O] {
+	index := make(map[any][]int)
+	for j, item := range right.data {
+		if getFieldValue(item, rightOn) == nil {
+			continue
+		}
+		if key, ok := joinKey(item, keys.right); ok {
+			index[key] = append(index[key], j)
+		}
+	}
+	for _, rows := range index {
+		sort.SliceStable(rows, func(a, b int) bool {
+			return compareValues(getFieldValue(right.data[rows[a]], rightOn), getFieldValue(right.data[rows[b]], rightOn)) < 0
+		})
+	}
+
+	pairs := make([]joinPair, len(left.data))
+	for i, item := range left.data {
+		pairs[i] = joinPair{left: i, right: -1}
+
+		val := getFieldValue(item, leftOn)
+		key, ok := joinKey(item, keys.left)
+		if val == nil || !ok {
+			continue
+		}
+
+		rows := index[key]
+		n := sort.Search(len(rows), func(k int) bool {
+			return compareValues(getFieldValue(right.data[rows[k]], rightOn), val) > 0
+		})
+		if n > 0 {
+			pairs[i].right = rows[n-1]
+		}
+	}
+
+	return buildJoin(left, right, pairs, func(l *L, r *R) O { return combine(*l, r) })
+}
+
+type joinPair struct {
+	left, right int
+}
+
+func hashJoin[L, R any](left *Pipeline[L], right *Pipeline[R], leftKeys, rightKeys []string, keepLeft, keepRight bool) []joinPair {
+	index := make(map[any][]int)
+	for j, item := range right.data {
+		if key, ok := joinKey(item, rightKeys); ok {
+			index[key] = append(index[key], j)
+		}
+	}
+
+	matched := make([]bool, len(right.data))
+	pairs := make([]joinPair, 0, len(left.data))
+
+	for i, item := range left.data {
+		var rows []int
+		if key, ok := joinKey(item, leftKeys); ok {
+			rows = index[key]
+		}
+
+		for _, j := range rows {
+			pairs = append(pairs, joinPair{left: i, right: j})
+			matched[j] = true
+		}
+		if len(rows) == 0 && keepLeft {
+			pairs = append(pairs, joinPair{left: i, right: -1})
+		}
+	}
+
+	if keepRight {
+		for j := range right.data {
+			if !matched[j] {
+				pairs = append(pairs, joinPair{left: -1, right: j})
+			}
+		}
+	}
+
+	return pairs
+}
+
+func buildJoin[L, R, O any](left *Pipeline[L], right *Pipeline[R], pairs []joinPair, combine func(*L, *R) O) *Pipeline[O] {
+	result := make([]O, len(pairs))
+	resultIdx := make([]int, len(pairs))
+
+	for i, pair := range pairs {
+		var l *L
+		var r *R
+		if pair.left >= 0 {
+			lv := left.data[pair.left]
+			l = &lv
+			if pair.left < len(left.originalIndex) {
+				resultIdx[i] = left.originalIndex[pair.left]
+			}
+		}
+		if pair.right >= 0 {
+			rv := right.data[pair.right]
+			r = &rv
+		}
+		result[i] = combine(l, r)
+	}
+
+	return &Pipeline[O]{data: result, originalIndex: resultIdx}
+}
+
+func joinKey(item any, fields []string) (any, bool) {
+	if len(fields) == 1 {
+		val := joinValue(getFieldValue(item, fields[0]))
+		return val, val != nil
+	}
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		val := joinValue(getFieldValue(item, field))
+		if val == nil {
+			return nil, false
+		}
+		parts[i] = fmt.Sprintf("%T:%v", val, val)
+	}
+	return strings.Join(parts, "\x00"), true
+}
+
+// joinValue turns a field value into a key. Pointers are followed, and
+// numbers of any kind become int64 when they are whole (float64 otherwise),
+// so an int field matches an int64 or float64 column read from a database or
+// JSON. Times become UTC without a monotonic reading, so equal instants
+// match whatever their location.
+func joinValue(v any) any {
+	v = derefValue(v)
+	if v == nil {
+		return nil
+	}
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Round(0)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return int64(u)
+		}
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f)
+		}
+		return f
+	}
+	return valueKey(v)
+}
+
+// Keyed is implemented by every *Pipeline. It lets WhereIn and WhereNotIn
+// look up keys in a pipeline of a different element type.
+type Keyed interface {
+	keySet(fields []string) map[any]bool
+}
+
+func (p *Pipeline[T]) keySet(fields []string) map[any]bool {
+	keys := make(map[any]bool, len(p.data))
+	for _, item := range p.data {
+		if key, ok := joinKey(item, fields); ok {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// WhereIn keeps the rows whose field value appears in otherField of other
+// (a semi-join).
+func (p *Pipeline[T]) WhereIn(field string, other Keyed, otherField string) *Pipeline[T] {
+	return p.semiJoin(field, other, otherField, true)
+}
+
+// WhereNotIn keeps the rows whose field value does not appear in otherField
+// of other (an anti-join). Rows with a nil key are kept.
+func (p *Pipeline[T]) WhereNotIn(field string, other Keyed, otherField string) *Pipeline[T] {
+	return p.semiJoin(field, other, otherField, false)
+}
+
+func (p *Pipeline[T]) semiJoin(field string, other Keyed, otherField string, keep bool) *Pipeline[T] {
+	keys := other.keySet([]string{otherField})
+
+	positions := make([]int, 0)
+	for i, item := range p.data {
+		key, ok := joinKey(item, []string{field})
+		if (ok && keys[key]) == keep {
+			positions = append(positions, i)
+		}
+	}
+	return p.subset(positions)
+}
@@ -0,0 +1,344 @@
+package plygo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// joinManagers is the right side of the join tests: two managers in NYC,
+// one in Chicago and one in Boston, where none of samplePeople live.
+func joinManagers() []Person {
+	return []Person{
+		{"Mallory", 52, "NYC", 150000, true},
+		{"Oscar", 47, "Chicago", 140000, true},
+		{"Peggy", 45, "NYC", 145000, true},
+		{"Trent", 50, "Boston", 130000, false},
+	}
+}
+
+func TestJoin(t *testing.T) {
+	result := Join(From(samplePeople()[:4]), From(joinManagers()), "City", "City",
+		func(p, m Person) [2]string {
+			return [2]string{p.Name, m.Name}
+		})
+
+	expected := [][2]string{
+		{"Alice", "Mallory"},
+		{"Alice", "Peggy"},
+		{"Charlie", "Mallory"},
+		{"Charlie", "Peggy"},
+		{"Diana", "Oscar"},
+	}
+	if !reflect.DeepEqual(result.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Collect())
+	}
+	if !reflect.DeepEqual(result.Which(), []int{1, 1, 3, 3, 4}) {
+		t.Errorf("Expected left indices [1 1 3 3 4], got %v", result.Which())
+	}
+}
+
+func TestJoin_PreservesFilteredIndices(t *testing.T) {
+	people := From(samplePeople()).AtRow(4)
+
+	result := Join(people, From(joinManagers()), "City", "City",
+		func(p, m Person) string { return p.Name + ":" + m.Name })
+
+	if !reflect.DeepEqual(result.Which(), []int{4}) {
+		t.Errorf("Expected [4], got %v", result.Which())
+	}
+}
+
+func TestLeftJoin(t *testing.T) {
+	result := LeftJoin(From(samplePeople()[:4]), From(joinManagers()), "City", "City",
+		func(p Person, m *Person) [2]string {
+			if m == nil {
+				return [2]string{p.Name, ""}
+			}
+			return [2]string{p.Name, m.Name}
+		})
+
+	expected := [][2]string{
+		{"Alice", "Mallory"},
+		{"Alice", "Peggy"},
+		{"Bob", ""},
+		{"Charlie", "Mallory"},
+		{"Charlie", "Peggy"},
+		{"Diana", "Oscar"},
+	}
+	if !reflect.DeepEqual(result.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Collect())
+	}
+	if !reflect.DeepEqual(result.Which(), []int{1, 1, 2, 3, 3, 4}) {
+		t.Errorf("Expected [1 1 2 3 3 4], got %v", result.Which())
+	}
+}
+
+func TestRightJoin(t *testing.T) {
+	result := RightJoin(From(samplePeople()[:4]), From(joinManagers()), "City", "City",
+		func(p *Person, m Person) [2]string {
+			if p == nil {
+				return [2]string{"", m.Name}
+			}
+			return [2]string{p.Name, m.Name}
+		})
+
+	if result.Count() != 6 {
+		t.Fatalf("Expected 6 rows, got %d", result.Count())
+	}
+	last, _ := result.Last()
+	if last != [2]string{"", "Trent"} {
+		t.Errorf("Expected unmatched Trent last, got %v", last)
+	}
+	if !reflect.DeepEqual(result.Which(), []int{1, 1, 3, 3, 4, 0}) {
+		t.Errorf("Expected [1 1 3 3 4 0], got %v", result.Which())
+	}
+}
+
+func TestFullJoin(t *testing.T) {
+	result := FullJoin(From(samplePeople()[:4]), From(joinManagers()), "City", "City",
+		func(p, m *Person) [2]string {
+			var row [2]string
+			if p != nil {
+				row[0] = p.Name
+			}
+			if m != nil {
+				row[1] = m.Name
+			}
+			return row
+		})
+
+	expected := [][2]string{
+		{"Alice", "Mallory"},
+		{"Alice", "Peggy"},
+		{"Bob", ""},
+		{"Charlie", "Mallory"},
+		{"Charlie", "Peggy"},
+		{"Diana", "Oscar"},
+		{"", "Trent"},
+	}
+	if !reflect.DeepEqual(result.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Collect())
+	}
+}
+
+func TestJoin_Empty(t *testing.T) {
+	none := From([]Person{})
+	if inner := Join(none, From(joinManagers()), "City", "City", func(p, m Person) string { return m.Name }); inner.Count() != 0 {
+		t.Errorf("Expected no rows, got %v", inner.Collect())
+	}
+	right := RightJoin(none, From(joinManagers()), "City", "City", func(p *Person, m Person) string { return m.Name })
+	if right.Count() != 4 || !reflect.DeepEqual(right.Which(), []int{0, 0, 0, 0}) {
+		t.Errorf("Expected every manager unmatched, got %v %v", right.Collect(), right.Which())
+	}
+}
+
+func TestJoin_TimeKeys(t *testing.T) {
+	now := time.Now()
+	left := From([]map[string]any{{"at": now, "day": "Mon", "x": 1}})
+	right := From([]map[string]any{
+		{"at": now.Round(0), "day": "Mon", "y": 10},
+		{"at": now.In(time.FixedZone("UTC+5", 5*60*60)), "day": "Mon", "y": 20},
+		{"at": now.UTC(), "day": "Tue", "y": 30},
+	})
+	sum := func(l, r map[string]any) int { return l["x"].(int) + r["y"].(int) }
+
+	if got := Join(left, right, "at", "at", sum).Collect(); !reflect.DeepEqual(got, []int{11, 21, 31}) {
+		t.Errorf("Expected equal instants to match, got %v", got)
+	}
+	if got := JoinOn(left, right, On("at", "day"), sum).Collect(); !reflect.DeepEqual(got, []int{11, 21}) {
+		t.Errorf("Expected equal instants to match on a composite key, got %v", got)
+	}
+	if got := right.WhereIn("at", left, "at").Count(); got != 3 {
+		t.Errorf("Expected every instant in WhereIn, got %d", got)
+	}
+}
+
+func TestJoin_MapPipelines(t *testing.T) {
+	left := From([]map[string]any{{"k": "a", "x": 1}, {"k": nil, "x": 2}})
+	right := From([]map[string]any{{"k": "a", "y": 10}, {"k": nil, "y": 20}})
+
+	result := Join(left, right, "k", "k", func(l, r map[string]any) int {
+		return l["x"].(int) + r["y"].(int)
+	})
+
+	if !reflect.DeepEqual(result.Collect(), []int{11}) {
+		t.Errorf("Expected nil keys not to match, got %v", result.Collect())
+	}
+}
+
+func TestWhereIn(t *testing.T) {
+	result := From(samplePeople()[:4]).WhereIn("City", From(joinManagers()), "City")
+
+	names := make([]string, 0)
+	for _, p := range result.Collect() {
+		names = append(names, p.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Alice", "Charlie", "Diana"}) {
+		t.Errorf("Expected Alice, Charlie and Diana, got %v", names)
+	}
+	if !reflect.DeepEqual(result.Which(), []int{1, 3, 4}) {
+		t.Errorf("Expected [1 3 4], got %v", result.Which())
+	}
+}
+
+func TestWhereNotIn(t *testing.T) {
+	managers := From(joinManagers())
+	result := managers.WhereNotIn("City", From(samplePeople()), "City")
+
+	if result.Count() != 1 || result.Collect()[0].Name != "Trent" {
+		t.Errorf("Expected only Trent, got %v", result.Collect())
+	}
+	if !reflect.DeepEqual(result.Which(), []int{4}) {
+		t.Errorf("Expected [4], got %v", result.Which())
+	}
+}
+
+func TestWhereIn_MixedKeys(t *testing.T) {
+	thirty := 30
+	keys := From([]map[string]any{{"age": &thirty}, {"age": (*int)(nil)}, {"age": int64(35)}, {"age": 27.5}})
+
+	if got := From(samplePeople()[:4]).WhereIn("Age", keys, "age").Which(); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("Expected pointer and int64 keys to match by value, got %v", got)
+	}
+	if got := From(samplePeople()[:4]).WhereNotIn("Age", keys, "age").Which(); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Expected Bob and Diana to be missing, got %v", got)
+	}
+}
+
+func TestWhereIn_MapSource(t *testing.T) {
+	offices := From([]map[string]any{{"City": "Chicago"}, {"City": nil}})
+
+	result := From(samplePeople()[:4]).WhereIn("City", offices, "City")
+	if result.Count() != 1 || result.Collect()[0].Name != "Diana" {
+		t.Errorf("Expected Diana, got %v", result.Collect())
+	}
+}
+
+func TestWhereIn_Empty(t *testing.T) {
+	none := From([]Person{})
+	if got := From(samplePeople()).WhereIn("City", none, "City"); got.Count() != 0 {
+		t.Errorf("Expected no rows against an empty source, got %v", got.Collect())
+	}
+	if got := From(samplePeople()).WhereNotIn("City", none, "City"); got.Count() != 7 {
+		t.Errorf("Expected every row against an empty source, got %d", got.Count())
+	}
+}
+
+func TestJoin_MixedNumericKeys(t *testing.T) {
+	rows := From([]map[string]any{
+		{"age": int64(30), "team": "EU"},
+		{"age": 35.0, "team": "US"},
+		{"age": 27.5, "team": "US"},
+		{"age": uint8(25), "team": "EU"},
+	})
+
+	names := Join(From(samplePeople()[:4]), rows, "Age", "age", func(p Person, r map[string]any) string {
+		return p.Name
+	})
+	if !reflect.DeepEqual(names.Collect(), []string{"Alice", "Bob", "Charlie"}) {
+		t.Errorf("Expected int keys to match int64, uint8 and whole float64 keys, got %v", names.Collect())
+	}
+
+	accounts := From([]map[string]any{{"age": int64(25), "name": "Bob"}, {"age": 35.0, "name": "Bob"}})
+	composite := JoinOn(From(samplePeople()[:4]), accounts, On("Age", "Name").Right("age", "name"),
+		func(p Person, r map[string]any) string { return p.Name })
+	if !reflect.DeepEqual(composite.Which(), []int{2}) {
+		t.Errorf("Expected composite keys to match across numeric types, got %v", composite.Which())
+	}
+}
+
+func TestJoinOn_CompositeKey(t *testing.T) {
+	people := From(samplePeople()[:4])
+	managers := From(joinManagers())
+
+	pairs := JoinOn(people, managers, On("City", "Active"),
+		func(p, m Person) string { return p.Name + ":" + m.Name })
+
+	if !reflect.DeepEqual(pairs.Collect(), []string{"Alice:Mallory", "Alice:Peggy", "Diana:Oscar"}) {
+		t.Errorf("Expected [Alice:Mallory Alice:Peggy Diana:Oscar], got %v", pairs.Collect())
+	}
+	if !reflect.DeepEqual(pairs.Which(), []int{1, 1, 4}) {
+		t.Errorf("Expected [1 1 4], got %v", pairs.Which())
+	}
+
+	missing := LeftJoinOn(people, managers, On("City", "Active"),
+		func(p Person, m *Person) bool { return m == nil })
+	if !reflect.DeepEqual(missing.Collect(), []bool{false, false, true, true, false}) {
+		t.Errorf("Expected Bob and the inactive Charlie unmatched, got %v", missing.Collect())
+	}
+}
+
+type Trade struct {
+	Symbol string
+	At     time.Time
+	Qty    int
+}
+
+type Quote struct {
+	Symbol string
+	At     time.Time
+	Price  float64
+}
+
+type Session struct {
+	Name  string
+	Start time.Time
+	End   any
+}
+
+func at(hour int) time.Time {
+	return time.Date(2024, time.January, 2, hour, 0, 0, 0, time.UTC)
+}
+
+func TestRangeJoin(t *testing.T) {
+	trades := From([]Trade{
+		{"ACME", at(9), 1},
+		{"ACME", at(12), 2},
+		{"ACME", at(18), 3},
+	})
+	periods := From([]Session{
+		{"morning", at(8), at(12)},
+		{"afternoon", at(12), at(17)},
+		{"evening", at(17), nil},
+	})
+
+	result := RangeJoin(trades, periods, On(), "At", "Start", "End",
+		func(tr Trade, p Session) string { return p.Name })
+
+	expected := []string{"morning", "afternoon", "evening"}
+	if !reflect.DeepEqual(result.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Collect())
+	}
+}
+
+func TestAsOfJoin(t *testing.T) {
+	trades := From([]Trade{
+		{"ACME", at(8), 1},
+		{"ACME", at(10), 2},
+		{"INIT", at(11), 3},
+		{"ACME", at(13), 4},
+	})
+	quotes := From([]Quote{
+		{"ACME", at(12), 101},
+		{"ACME", at(9), 100},
+		{"INIT", at(11), 50},
+		{"ACME", at(10), 100.5},
+	})
+
+	prices := AsOfJoin(trades, quotes, On("Symbol"), "At", "At",
+		func(tr Trade, q *Quote) float64 {
+			if q == nil {
+				return 0
+			}
+			return q.Price
+		})
+
+	expected := []float64{0, 100.5, 50, 101}
+	if !reflect.DeepEqual(prices.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, prices.Collect())
+	}
+	if !reflect.DeepEqual(prices.Which(), []int{1, 2, 3, 4}) {
+		t.Errorf("Expected [1 2 3 4], got %v", prices.Which())
+	}
+}
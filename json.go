@@ -0,0 +1,363 @@
+package plygo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+)
+
+type JSONConfig struct {
+	indent string
+	strict bool
+	skip   bool
+	report *ReadReport
+}
+
+type JSONOption func(*JSONConfig)
+
+// JSONIndent pretty-prints WriteJSON output with the given indent.
+func JSONIndent(indent string) JSONOption {
+	return func(c *JSONConfig) { c.indent = indent }
+}
+
+// JSONStrict rejects objects with fields that do not exist in T.
+func JSONStrict() JSONOption {
+	return func(c *JSONConfig) { c.strict = true }
+}
+
+// JSONSkipMalformed skips elements or lines that fail to decode instead of
+// stopping at the first one. Skipped rows are recorded in report, which may
+// be nil. Syntax errors in a JSON array still stop decoding, since the rest
+// of the array cannot be located.
+func JSONSkipMalformed(report *ReadReport) JSONOption {
+	return func(c *JSONConfig) {
+		c.skip = true
+		c.report = report
+	}
+}
+
+// FromJSON decodes a JSON array into a pipeline, one element at a time.
+// Original indices are the 1-based element positions in the array.
+func FromJSON[T any](r io.Reader, options ...JSONOption) (*Pipeline[T], error) {
+	config := &JSONConfig{}
+	for _, opt := range options {
+		opt(config)
+	}
+
+	lines := &lineReader{r: r}
+	dec := json.NewDecoder(lines)
+
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return From([]T{}), nil
+	}
+	if err != nil {
+		return nil, jsonSyntaxError(err, lines)
+	}
+	if tok != json.Delim('[') {
+		return nil, &RowError{Line: lines.line(int(dec.InputOffset())), Err: errors.New("expected a JSON array")}
+	}
+
+	result := make([]T, 0)
+	resultIdx := make([]int, 0)
+	element := 0
+
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, jsonSyntaxError(err, lines)
+		}
+		element++
+
+		var item T
+		if err := decodeJSON(raw, &item, config); err != nil {
+			start := int(dec.InputOffset()) - len(raw)
+			rowErr := &RowError{Line: lines.line(start), Err: err}
+			if !config.skip {
+				return nil, rowErr
+			}
+			if config.report != nil {
+				config.report.Skipped = append(config.report.Skipped, rowErr)
+			}
+			continue
+		}
+
+		result = append(result, item)
+		resultIdx = append(resultIdx, element)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, jsonSyntaxError(err, lines)
+	}
+
+	return &Pipeline[T]{data: result, originalIndex: resultIdx}, nil
+}
+
+// FromNDJSON decodes newline-delimited JSON, one value per line. Blank
+// lines are ignored. Original indices are the 1-based line numbers.
+func FromNDJSON[T any](r io.Reader, options ...JSONOption) (*Pipeline[T], error) {
+	config := &JSONConfig{}
+	for _, opt := range options {
+		opt(config)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	result := make([]T, 0)
+	resultIdx := make([]int, 0)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var item T
+		if err := decodeJSON(text, &item, config); err != nil {
+			rowErr := &RowError{Line: line, Err: err}
+			if !config.skip {
+				return nil, rowErr
+			}
+			if config.report != nil {
+				config.report.Skipped = append(config.report.Skipped, rowErr)
+			}
+			continue
+		}
+
+		result = append(result, item)
+		resultIdx = append(resultIdx, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Pipeline[T]{data: result, originalIndex: resultIdx}, nil
+}
+
+func decodeJSON(data []byte, v any, config *JSONConfig) error {
+	if !config.strict {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// WriteJSON writes the pipeline as a JSON array. Structs are encoded with
+// encoding/json; map rows keep the pipeline's column order.
+func (p *Pipeline[T]) WriteJSON(w io.Writer, options ...JSONOption) error {
+	return writeJSON(w, p.jsonRows(), options)
+}
+
+// WriteNDJSON writes one JSON value per line.
+func (p *Pipeline[T]) WriteNDJSON(w io.Writer) error {
+	return writeNDJSON(w, p.jsonRows())
+}
+
+// WriteJSON writes the selected fields as an array of objects whose keys
+// follow the selection order.
+func (s *Selection[T]) WriteJSON(w io.Writer, options ...JSONOption) error {
+	return writeJSON(w, s.jsonRows(), options)
+}
+
+// WriteNDJSON writes the selected fields, one object per line.
+func (s *Selection[T]) WriteNDJSON(w io.Writer) error {
+	return writeNDJSON(w, s.jsonRows())
+}
+
+// WriteJSON writes the groups as an array of {"key": ..., "value": [...]}
+// objects in order of first appearance. Multi-field keys are written as an
+// object of the grouping fields.
+func (g *Grouping[T]) WriteJSON(w io.Writer, options ...JSONOption) error {
+	return writeJSON(w, g.jsonRows(), options)
+}
+
+// WriteNDJSON writes one {"key": ..., "value": [...]} object per line.
+func (g *Grouping[T]) WriteNDJSON(w io.Writer) error {
+	return writeNDJSON(w, g.jsonRows())
+}
+
+// WriteGroupsJSON writes an aggregate such as Grouping.Count or
+// Grouping.Sum as an array of {"key": ..., "value": ...} objects sorted by
+// key.
+func WriteGroupsJSON[K comparable, V any](w io.Writer, result map[K]V, options ...JSONOption) error {
+	return writeJSON(w, keyValueRows(result), options)
+}
+
+// WriteGroupsNDJSON writes an aggregate as one {"key": ..., "value": ...}
+// object per line, sorted by key.
+func WriteGroupsNDJSON[K comparable, V any](w io.Writer, result map[K]V) error {
+	return writeNDJSON(w, keyValueRows(result))
+}
+
+// orderedObject is a JSON object whose keys are written in a fixed order,
+// unlike a Go map.
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (p *Pipeline[T]) jsonRows() []any {
+	rows := make([]any, len(p.data))
+	maps, isMap := any(p.data).([]map[string]any)
+	if !isMap {
+		for i, item := range p.data {
+			rows[i] = item
+		}
+		return rows
+	}
+
+	_, fields := p.exportColumns()
+	for i, item := range maps {
+		rows[i] = orderedObject{keys: fields, values: item}
+	}
+	return rows
+}
+
+func (s *Selection[T]) jsonRows() []any {
+	data := s.execute()
+	rows := make([]any, len(data))
+	for i, item := range data {
+		rows[i] = orderedObject{keys: s.fields, values: item}
+	}
+	return rows
+}
+
+func (g *Grouping[T]) jsonRows() []any {
+	groups := g.Groups()
+	rows := make([]any, len(groups))
+	for i, group := range groups {
+		rows[i] = orderedObject{
+			keys: []string{"key", "value"},
+			values: map[string]any{
+				"key":   g.jsonKey(group),
+				"value": group.Rows.jsonRows(),
+			},
+		}
+	}
+	return rows
+}
+
+// jsonKey recovers a readable key from the first row of a group, since
+// internal keys for multi-field and unhashable values are opaque strings.
+func (g *Grouping[T]) jsonKey(group Group[T]) any {
+	if g.bucket != nil || len(group.Rows.data) == 0 {
+		return group.Key
+	}
+
+	first := group.Rows.data[0]
+	if len(g.fields) == 0 {
+		return getFieldValue(first, g.field)
+	}
+
+	values := make(map[string]any, len(g.fields))
+	for _, field := range g.fields {
+		values[field] = getFieldValue(first, field)
+	}
+	return orderedObject{keys: g.fields, values: values}
+}
+
+func keyValueRows[K comparable, V any](result map[K]V) []any {
+	keys := make([]K, 0, len(result))
+	for key := range result {
+		keys = append(keys, key)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compareValues(keys[i], keys[j]) < 0
+	})
+
+	rows := make([]any, len(keys))
+	for i, key := range keys {
+		rows[i] = orderedObject{
+			keys:   []string{"key", "value"},
+			values: map[string]any{"key": key, "value": result[key]},
+		}
+	}
+	return rows
+}
+
+func writeJSON(w io.Writer, rows []any, options []JSONOption) error {
+	config := &JSONConfig{}
+	for _, opt := range options {
+		opt(config)
+	}
+
+	enc := json.NewEncoder(w)
+	if config.indent != "" {
+		enc.SetIndent("", config.indent)
+	}
+	return enc.Encode(rows)
+}
+
+func writeNDJSON(w io.Writer, rows []any) error {
+	enc := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineReader remembers where newlines occur in the bytes read through it,
+// so decoder offsets can be reported as line numbers.
+type lineReader struct {
+	r        io.Reader
+	offset   int
+	newlines []int
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			l.newlines = append(l.newlines, l.offset+i)
+		}
+	}
+	l.offset += n
+	return n, err
+}
+
+func (l *lineReader) line(offset int) int {
+	return sort.SearchInts(l.newlines, offset) + 1
+}
+
+func jsonSyntaxError(err error, lines *lineReader) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &RowError{Line: lines.line(int(syntaxErr.Offset)), Err: err}
+	}
+	if err == io.EOF {
+		return &RowError{Line: lines.line(lines.offset), Err: io.ErrUnexpectedEOF}
+	}
+	return err
+}
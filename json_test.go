@@ -0,0 +1,222 @@
+package plygo
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type JSONPerson struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+	City string `json:"city,omitempty"`
+}
+
+func TestFromJSON(t *testing.T) {
+	input := `[
+  {"name": "Alice", "age": 30, "city": "NYC"},
+  {"name": "Bob", "age": 25}
+]`
+
+	p, err := FromJSON[JSONPerson](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []JSONPerson{{"Alice", 30, "NYC"}, {"Bob", 25, ""}}
+	if !reflect.DeepEqual(p.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, p.Collect())
+	}
+	if !reflect.DeepEqual(p.Which(), []int{1, 2}) {
+		t.Errorf("Expected indices [1 2], got %v", p.Which())
+	}
+}
+
+func TestFromJSONErrors(t *testing.T) {
+	input := `[
+  {"name": "Alice", "age": 30},
+  {"name": "Bob", "age": "old"},
+  {"name": "Carol", "age": 28}
+]`
+
+	_, err := FromJSON[JSONPerson](strings.NewReader(input))
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 3 {
+		t.Fatalf("Expected RowError on line 3, got %v", err)
+	}
+
+	var report ReadReport
+	p, err := FromJSON[JSONPerson](strings.NewReader(input), JSONSkipMalformed(&report))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(p.Which(), []int{1, 3}) {
+		t.Errorf("Expected indices [1 3], got %v", p.Which())
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Line != 3 {
+		t.Errorf("Expected one skipped row on line 3, got %v", report.Skipped)
+	}
+
+	_, err = FromJSON[JSONPerson](strings.NewReader("[\n{\"name\": \"A\"},\n{\"name\" \"B\"}\n]"))
+	if !errors.As(err, &rowErr) || rowErr.Line != 3 {
+		t.Errorf("Expected syntax error on line 3, got %v", err)
+	}
+
+	_, err = FromJSON[JSONPerson](strings.NewReader(`{"name": "A"}`))
+	if err == nil {
+		t.Error("Expected error for non-array input")
+	}
+
+	_, err = FromJSON[JSONPerson](strings.NewReader(`[{"name": "A", "extra": 1}]`), JSONStrict())
+	if err == nil {
+		t.Error("Expected error for unknown field in strict mode")
+	}
+}
+
+func TestFromNDJSON(t *testing.T) {
+	input := "{\"name\": \"Alice\", \"age\": 30}\n\n{\"name\": \"Bob\", \"age\": }\n{\"name\": \"Carol\", \"age\": 28}\n"
+
+	_, err := FromNDJSON[JSONPerson](strings.NewReader(input))
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 3 {
+		t.Fatalf("Expected RowError on line 3, got %v", err)
+	}
+
+	var report ReadReport
+	p, err := FromNDJSON[map[string]any](strings.NewReader(input), JSONSkipMalformed(&report))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Count() != 2 || p.Collect()[1]["name"] != "Carol" {
+		t.Errorf("Unexpected rows %v", p.Collect())
+	}
+	if !reflect.DeepEqual(p.Which(), []int{1, 4}) {
+		t.Errorf("Expected line numbers [1 4], got %v", p.Which())
+	}
+	if len(report.Skipped) != 1 {
+		t.Errorf("Expected one skipped line, got %v", report.Skipped)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	people := []JSONPerson{{"Alice", 30, "NYC"}, {"Bob", 25, ""}}
+
+	var buf strings.Builder
+	if err := From(people).WriteJSON(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `[{"name":"Alice","age":30,"city":"NYC"},{"name":"Bob","age":25}]` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	back, err := FromJSON[JSONPerson](strings.NewReader(buf.String()))
+	if err != nil || !reflect.DeepEqual(back.Collect(), people) {
+		t.Errorf("Round trip failed: %v, %v", back, err)
+	}
+
+	buf.Reset()
+	if err := From(people).WriteNDJSON(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = "{\"name\":\"Alice\",\"age\":30,\"city\":\"NYC\"}\n{\"name\":\"Bob\",\"age\":25}\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestJSONEmptyAndNaN(t *testing.T) {
+	for _, input := range []string{"", "[]"} {
+		p, err := FromJSON[JSONPerson](strings.NewReader(input))
+		if err != nil || p.Count() != 0 {
+			t.Errorf("Expected no rows for %q, got %v, %v", input, p, err)
+		}
+	}
+	if p, err := FromNDJSON[JSONPerson](strings.NewReader("\n\n")); err != nil || p.Count() != 0 {
+		t.Errorf("Expected no rows from blank lines, got %v, %v", p, err)
+	}
+
+	var buf strings.Builder
+	if err := From([]Person{}).WriteJSON(&buf); err != nil || buf.String() != "[]\n" {
+		t.Errorf("Expected an empty array, got %q, %v", buf.String(), err)
+	}
+
+	people := []Person{{"Alice", 30, "NYC", math.NaN(), true}}
+	if err := From(people).WriteJSON(&buf); err == nil || !strings.Contains(err.Error(), "NaN") {
+		t.Errorf("Expected an unsupported value error, got %v", err)
+	}
+	if err := From(people).Select("Name", "Salary").WriteNDJSON(&buf); err == nil || !strings.Contains(err.Error(), "NaN") {
+		t.Errorf("Expected an unsupported value error for a selection, got %v", err)
+	}
+}
+
+func TestWriteJSONFieldOrder(t *testing.T) {
+	people := []Person{{"Alice", 30, "NYC", 75000, true}}
+
+	var buf strings.Builder
+	if err := From(people).Select("Salary", "Name").WriteJSON(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `[{"Salary":75000,"Name":"Alice"}]` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	renamed := From(people).Select("Name", "Age").Rename("Name", "who")
+	if err := renamed.WriteJSON(&buf, JSONIndent("  ")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = "[\n  {\n    \"who\": \"Alice\",\n    \"Age\": 30\n  }\n]\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestGroupingWriteJSON(t *testing.T) {
+	people := []Person{
+		{"Alice", 30, "NYC", 75000, true},
+		{"Bob", 25, "LA", 60000, true},
+		{"Charlie", 35, "NYC", 90000, false},
+	}
+
+	var buf strings.Builder
+	if err := From(people).Select("City", "Name").GroupBy("City").WriteJSON(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `[{"key":"NYC","value":[{"City":"NYC","Name":"Alice"},{"City":"NYC","Name":"Charlie"}]},` +
+		`{"key":"LA","value":[{"City":"LA","Name":"Bob"}]}]` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := From(people).Select("Salary", "Name", "City").GroupBy("City").WriteJSON(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = `[{"key":"NYC","value":[{"Salary":75000,"Name":"Alice","City":"NYC"},{"Salary":90000,"Name":"Charlie","City":"NYC"}]},` +
+		`{"key":"LA","value":[{"Salary":60000,"Name":"Bob","City":"LA"}]}]` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected selected column order, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := From(people).Select("Name", "Age").Where("Age").GreaterThan(26).GroupBy("Age").WriteJSON(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = `[{"key":30,"value":[{"Name":"Alice","Age":30}]},{"key":35,"value":[{"Name":"Charlie","Age":35}]}]` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected selected column order, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteGroupsNDJSON(&buf, From(people).GroupBy("City").Count()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = "{\"key\":\"LA\",\"value\":1}\n{\"key\":\"NYC\",\"value\":2}\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,175 @@
+package plygo
+
+import (
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Map converts every row to another type, keeping original indices.
+func Map[T, U any](p *Pipeline[T], fn func(T) U) *Pipeline[U] {
+	return FilterMap(p, func(item T) (U, bool) {
+		return fn(item), true
+	})
+}
+
+// FlatMap converts every row to zero or more rows of another type. Each
+// output row keeps the original index of the row it came from.
+func FlatMap[T, U any](p *Pipeline[T], fn func(T) []U) *Pipeline[U] {
+	result := make([]U, 0, len(p.data))
+	resultIdx := make([]int, 0, len(p.data))
+	var resultSrc []int
+
+	for i, item := range p.data {
+		for _, out := range fn(item) {
+			result = append(result, out)
+			resultIdx = append(resultIdx, p.indexAt(i))
+			if p.sources != nil {
+				resultSrc = append(resultSrc, p.sources[i])
+			}
+		}
+	}
+
+	return &Pipeline[U]{data: result, originalIndex: resultIdx, sources: resultSrc}
+}
+
+// FilterMap converts rows to another type and drops those for which fn
+// returns false.
+func FilterMap[T, U any](p *Pipeline[T], fn func(T) (U, bool)) *Pipeline[U] {
+	return FlatMap(p, func(item T) []U {
+		if out, ok := fn(item); ok {
+			return []U{out}
+		}
+		return nil
+	})
+}
+
+func (p *Pipeline[T]) indexAt(pos int) int {
+	if pos < len(p.originalIndex) {
+		return p.originalIndex[pos]
+	}
+	return 0
+}
+
+// SelectInto copies fields of each row into a new struct type U. A field of
+// U is filled from the source field with the same name, or with the name
+// given in its `plygo:"..."` tag; numeric fields are converted between
+// types. When fields are given, only those source fields are copied.
+func SelectInto[T, U any](p *Pipeline[T], fields ...string) *Pipeline[U] {
+	var zero U
+	typ := reflect.TypeOf(zero)
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return Map(p, func(T) U { return zero })
+	}
+
+	allowed := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		allowed[f] = true
+	}
+
+	targets := make([]int, 0, typ.NumField())
+	sources := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		name := fieldKey(sf)
+		if !sf.IsExported() || name == "" || (len(fields) > 0 && !allowed[name]) {
+			continue
+		}
+		targets = append(targets, i)
+		sources = append(sources, name)
+	}
+
+	return Map(p, func(item T) U {
+		var out U
+		dst := reflect.ValueOf(&out).Elem()
+		for k, i := range targets {
+			val := lookupField(item, sources[k])
+			if val != nil {
+				assignValue(dst.Field(i), reflect.ValueOf(val))
+			}
+		}
+		return out
+	})
+}
+
+// fieldKey returns the name a struct field is known by: its plygo tag if
+// present, otherwise the Go field name. A tag of "-" hides the field.
+func fieldKey(sf reflect.StructField) string {
+	tag := sf.Tag.Get("plygo")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return sf.Name
+}
+
+// lookupField is getFieldValue that also matches struct fields by tag.
+func lookupField(item any, name string) any {
+	if val := getFieldValue(item, name); val != nil {
+		return val
+	}
+
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).IsExported() && fieldKey(typ.Field(i)) == name {
+			return v.Field(i).Interface()
+		}
+	}
+	return nil
+}
+
+// assignValue stores src in dst when the types allow it. Pointers are
+// followed or allocated as needed, numbers are converted between kinds, and
+// NaN or infinite floats are never stored in integer fields.
+func assignValue(dst, src reflect.Value) bool {
+	if !dst.CanSet() || !src.IsValid() {
+		return false
+	}
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return true
+	}
+	if src.Kind() == reflect.Ptr && dst.Kind() != reflect.Ptr {
+		return !src.IsNil() && assignValue(dst, src.Elem())
+	}
+	if dst.Kind() == reflect.Ptr && src.Kind() != reflect.Ptr {
+		elem := reflect.New(dst.Type().Elem())
+		if !assignValue(elem.Elem(), src) {
+			return false
+		}
+		dst.Set(elem)
+		return true
+	}
+	if isNumberKind(src.Kind()) && isNumberKind(dst.Kind()) {
+		if src.CanFloat() && !dst.CanFloat() && (math.IsNaN(src.Float()) || math.IsInf(src.Float(), 0)) {
+			return false
+		}
+		dst.Set(src.Convert(dst.Type()))
+		return true
+	}
+	if src.Kind() == dst.Kind() && src.Type().ConvertibleTo(dst.Type()) {
+		dst.Set(src.Convert(dst.Type()))
+		return true
+	}
+	return false
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package plygo
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type PersonView struct {
+	Label string
+	Rich  bool
+}
+
+func TestMap(t *testing.T) {
+	views := Map(From(samplePeople()[:3]).AtRow(3, 1), func(p Person) PersonView {
+		return PersonView{p.Name + " (" + p.City + ")", p.Salary > 70000}
+	})
+
+	expected := []PersonView{{"Charlie (NYC)", true}, {"Alice (NYC)", true}}
+	if !reflect.DeepEqual(views.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, views.Collect())
+	}
+	if !reflect.DeepEqual(views.Which(), []int{3, 1}) {
+		t.Errorf("Expected [3 1], got %v", views.Which())
+	}
+
+	count := views.Where("Rich").IsTrue().Collect()
+	if len(count) != 2 {
+		t.Errorf("Expected mapped pipeline to be filterable, got %v", count)
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	letters := FlatMap(From(samplePeople()[:3]), func(p Person) []string {
+		return strings.Split(p.Name[:2], "")
+	})
+
+	if !reflect.DeepEqual(letters.Collect(), []string{"A", "l", "B", "o", "C", "h"}) {
+		t.Errorf("Unexpected letters: %v", letters.Collect())
+	}
+	if !reflect.DeepEqual(letters.Which(), []int{1, 1, 2, 2, 3, 3}) {
+		t.Errorf("Expected duplicated indices, got %v", letters.Which())
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	names := FilterMap(From(samplePeople()[:3]), func(p Person) (string, bool) {
+		return p.Name, p.City == "NYC"
+	})
+
+	if !reflect.DeepEqual(names.Collect(), []string{"Alice", "Charlie"}) {
+		t.Errorf("Expected NYC names, got %v", names.Collect())
+	}
+	if !reflect.DeepEqual(names.Which(), []int{1, 3}) {
+		t.Errorf("Expected [1 3], got %v", names.Which())
+	}
+}
+
+func TestMap_KeepsSources(t *testing.T) {
+	people := From(samplePeople()[:3])
+	ages := Map(Concat(people.AtRow(1), people.AtRow(2)), func(p Person) int { return p.Age })
+
+	if !reflect.DeepEqual(ages.Sources(), []int{1, 2}) {
+		t.Errorf("Expected sources [1 2], got %v", ages.Sources())
+	}
+}
+
+func TestMap_Empty(t *testing.T) {
+	none := From([]Person{})
+	if got := Map(none, func(p Person) string { return p.Name }); got.Count() != 0 || len(got.Which()) != 0 {
+		t.Errorf("Expected an empty Map result, got %v", got.Collect())
+	}
+	if got := FlatMap(none, func(p Person) []string { return []string{p.Name} }); got.Count() != 0 {
+		t.Errorf("Expected an empty FlatMap result, got %v", got.Collect())
+	}
+	if got := FlatMap(From(samplePeople()[:3]), func(p Person) []string { return nil }); got.Count() != 0 || len(got.Which()) != 0 {
+		t.Errorf("Expected empty expansions to drop every row, got %v", got.Collect())
+	}
+}
+
+type PersonSummary struct {
+	Name   string
+	Town   string `plygo:"City"`
+	Salary int
+	Note   string
+}
+
+func TestSelectInto(t *testing.T) {
+	summaries := SelectInto[Person, PersonSummary](From(samplePeople()[:3]).AtRow(2, 3))
+
+	expected := []PersonSummary{
+		{"Bob", "LA", 60000, ""},
+		{"Charlie", "NYC", 90000, ""},
+	}
+	if !reflect.DeepEqual(summaries.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, summaries.Collect())
+	}
+	if !reflect.DeepEqual(summaries.Which(), []int{2, 3}) {
+		t.Errorf("Expected [2 3], got %v", summaries.Which())
+	}
+}
+
+func TestSelectInto_Fields(t *testing.T) {
+	summaries := SelectInto[Person, PersonSummary](From(samplePeople()[:3]), "Name", "City").Collect()
+
+	if summaries[0] != (PersonSummary{Name: "Alice", Town: "NYC"}) {
+		t.Errorf("Expected only Name and City to be copied, got %v", summaries[0])
+	}
+}
+
+func TestSelectInto_FromMaps(t *testing.T) {
+	rows := []map[string]any{
+		{"Name": "Dora", "City": "Rome", "Salary": 51000.0},
+	}
+
+	summaries := SelectInto[map[string]any, PersonSummary](From(rows)).Collect()
+	if summaries[0] != (PersonSummary{"Dora", "Rome", 51000, ""}) {
+		t.Errorf("Unexpected summary: %v", summaries[0])
+	}
+}
+
+func TestSelectInto_PointersAndNaN(t *testing.T) {
+	type budget struct {
+		Name   string
+		Salary *float64
+	}
+	salary := 51000.0
+	rows := From([]budget{{"Dora", &salary}, {"Emil", nil}})
+
+	summaries := SelectInto[budget, PersonSummary](rows).Collect()
+	if summaries[0].Salary != 51000 || summaries[1].Salary != 0 {
+		t.Errorf("Expected pointees to be copied and nil to be skipped, got %v", summaries)
+	}
+
+	back := SelectInto[PersonSummary, budget](From(summaries)).Collect()
+	if back[0].Salary == nil || *back[0].Salary != 51000 {
+		t.Errorf("Expected a pointer field to be allocated, got %v", back[0].Salary)
+	}
+
+	people := samplePeople()[:2]
+	people[0].Salary = math.NaN()
+	people[1].Salary = math.Inf(1)
+	for _, s := range SelectInto[Person, PersonSummary](From(people)).Collect() {
+		if s.Salary != 0 {
+			t.Errorf("Expected non-finite salaries not to be stored in an int, got %v", s)
+		}
+	}
+}
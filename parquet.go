@@ -0,0 +1,1306 @@
+package plygo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ParquetCodec is the compression applied to Parquet pages.
+type ParquetCodec int
+
+const (
+	ParquetUncompressed ParquetCodec = 0
+	ParquetSnappy       ParquetCodec = 1
+	ParquetGzip         ParquetCodec = 2
+)
+
+// ParquetConfig holds the settings used by WriteParquet.
+type ParquetConfig struct {
+	codec        ParquetCodec
+	dictionary   bool
+	rowGroupSize int
+}
+
+// ParquetOption configures WriteParquet.
+type ParquetOption func(*ParquetConfig)
+
+// ParquetCompression sets the page compression (default ParquetSnappy).
+func ParquetCompression(codec ParquetCodec) ParquetOption {
+	return func(c *ParquetConfig) { c.codec = codec }
+}
+
+// ParquetDictionary enables or disables dictionary encoding (default on).
+// Even when enabled, a column is only dictionary-encoded when it repeats
+// values.
+func ParquetDictionary(enabled bool) ParquetOption {
+	return func(c *ParquetConfig) { c.dictionary = enabled }
+}
+
+// ParquetRowGroupSize sets the maximum number of rows per row group
+// (default 64K).
+func ParquetRowGroupSize(rows int) ParquetOption {
+	return func(c *ParquetConfig) { c.rowGroupSize = rows }
+}
+
+func defaultParquetConfig() *ParquetConfig {
+	return &ParquetConfig{codec: ParquetSnappy, dictionary: true, rowGroupSize: 64 * 1024}
+}
+
+// Parquet constants from parquet.thrift.
+const (
+	parquetBoolean   = 0
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetInt96     = 3
+	parquetFloat     = 4
+	parquetDouble    = 5
+	parquetByteArray = 6
+	parquetFixed     = 7
+
+	parquetRequired = 0
+	parquetOptional = 1
+	parquetRepeated = 2
+
+	parquetUTF8            = 0
+	parquetEnum            = 4
+	parquetDecimal         = 5
+	parquetDate            = 6
+	parquetTimestampMillis = 9
+	parquetTimestampMicros = 10
+	parquetUint8           = 11
+	parquetUint16          = 12
+	parquetUint32          = 13
+	parquetUint64          = 14
+	parquetInt8            = 15
+	parquetInt16           = 16
+	parquetJSON            = 19
+
+	parquetPlain           = 0
+	parquetPlainDictionary = 2
+	parquetRLE             = 3
+	parquetRLEDictionary   = 8
+
+	parquetDataPage       = 0
+	parquetDictionaryPage = 2
+	parquetDataPageV2     = 3
+
+	parquetMaxDictionary = 1 << 16
+)
+
+const errParquet = formatError("malformed parquet data")
+
+var parquetMagic = []byte("PAR1")
+
+// parquetKind says how a leaf's physical values map to Go values.
+type parquetKind int
+
+const (
+	parquetKindPlain parquetKind = iota
+	parquetKindString
+	parquetKindUnsigned
+	parquetKindDate
+	parquetKindMillis
+	parquetKindMicros
+	parquetKindNanos
+	parquetKindDecimal
+)
+
+// parquetNode is a schema element: a group with children or a leaf column.
+type parquetNode struct {
+	name       string
+	repetition int
+	children   []*parquetNode
+
+	physical   int
+	typeLength int
+	converted  int // -1 when absent
+	logical    thriftFields
+	kind       parquetKind
+	scale      int // decimal digits after the point
+
+	field  int  // struct field index when writing
+	format bool // written as text with formatCell
+}
+
+// parquetColumn is a leaf with the nodes on its path and, for each node,
+// the definition level at which it is present.
+type parquetColumn struct {
+	nodes  []*parquetNode
+	levels []int
+	maxDef int
+}
+
+func (c *parquetColumn) leaf() *parquetNode { return c.nodes[len(c.nodes)-1] }
+
+func (c *parquetColumn) path() []string {
+	path := make([]string, len(c.nodes))
+	for i, n := range c.nodes {
+		path[i] = n.name
+	}
+	return path
+}
+
+func parquetColumns(root *parquetNode) ([]*parquetColumn, error) {
+	var columns []*parquetColumn
+	var walk func(n *parquetNode, parent *parquetColumn) error
+	walk = func(n *parquetNode, parent *parquetColumn) error {
+		col := &parquetColumn{
+			nodes:  append(append([]*parquetNode(nil), parent.nodes...), n),
+			levels: append([]int(nil), parent.levels...),
+			maxDef: parent.maxDef,
+		}
+		switch n.repetition {
+		case parquetOptional:
+			col.maxDef++
+		case parquetRepeated:
+			return fmt.Errorf("repeated Parquet field %s is not supported", strings.Join(col.path(), "."))
+		}
+		col.levels = append(col.levels, col.maxDef)
+
+		if n.children == nil {
+			columns = append(columns, col)
+			return nil
+		}
+		for _, child := range n.children {
+			if err := walk(child, col); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, child := range root.children {
+		if err := walk(child, &parquetColumn{}); err != nil {
+			return nil, err
+		}
+	}
+	return columns, nil
+}
+
+// WriteParquet writes the pipeline as a Parquet file. Struct fields map to
+// columns by their `plygo` names, nested structs become groups and pointer
+// fields are optional. Integers, floats, bools, strings, []byte, time.Time
+// (microsecond timestamps) and durations (int64 nanoseconds) are written
+// natively; other types are written as strings. Map pipelines write one
+// optional column per key.
+func (p *Pipeline[T]) WriteParquet(w io.Writer, options ...ParquetOption) error {
+	config := defaultParquetConfig()
+	for _, opt := range options {
+		opt(config)
+	}
+	if config.rowGroupSize <= 0 {
+		config.rowGroupSize = len(p.data)
+	}
+
+	root, err := p.parquetSchema()
+	if err != nil {
+		return err
+	}
+	columns, err := parquetColumns(root)
+	if err != nil {
+		return err
+	}
+
+	cw := &countingWriter{w: w}
+	cw.write(parquetMagic)
+
+	rowGroups := make([]thriftFields, 0)
+	for start := 0; start < len(p.data); start += config.rowGroupSize {
+		end := min(start+config.rowGroupSize, len(p.data))
+		chunks := make([]thriftFields, len(columns))
+		size := int64(0)
+		for j, col := range columns {
+			values, defs := make([]any, end-start), make([]int, end-start)
+			for i, item := range p.data[start:end] {
+				values[i], defs[i] = parquetLeafValue(item, col)
+			}
+			var n int64
+			chunks[j], n = writeParquetChunk(cw, col, values, defs, config)
+			size += n
+		}
+		rowGroups = append(rowGroups, thriftFields{
+			{1, chunks},
+			{2, size},
+			{3, int64(end - start)},
+		})
+	}
+
+	meta := thriftFields{
+		{1, int32(1)},
+		{2, parquetSchemaElements(root)},
+		{3, int64(len(p.data))},
+		{4, rowGroups},
+		{6, "plyGO"},
+	}.encode()
+	cw.write(meta)
+	cw.write(binary.LittleEndian.AppendUint32(nil, uint32(len(meta))))
+	cw.write(parquetMagic)
+	return cw.err
+}
+
+func (p *Pipeline[T]) parquetSchema() (*parquetNode, error) {
+	root := &parquetNode{name: "schema", children: []*parquetNode{}}
+
+	var zero T
+	typ := reflect.TypeOf(zero)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ != nil && typ.Kind() == reflect.Struct {
+		children, err := parquetFields(typ, map[reflect.Type]bool{})
+		root.children = children
+		return root, err
+	}
+
+	headers, names := p.exportColumns()
+	schema := inferSchema(names, p.data)
+	for i, col := range schema.Columns {
+		ft := col.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		leaf := parquetLeaf(ft)
+		leaf.name, leaf.repetition, leaf.field = headers[i], parquetOptional, -1
+		root.children = append(root.children, leaf)
+	}
+	return root, nil
+}
+
+func parquetFields(typ reflect.Type, seen map[reflect.Type]bool) ([]*parquetNode, error) {
+	if seen[typ] {
+		return nil, fmt.Errorf("recursive type %s cannot be written to Parquet", typ)
+	}
+	seen[typ] = true
+	defer delete(seen, typ)
+
+	nodes := make([]*parquetNode, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		key := fieldKey(sf)
+		if !sf.IsExported() || key == "" {
+			continue
+		}
+
+		ft, repetition := sf.Type, parquetRequired
+		switch ft.Kind() {
+		case reflect.Ptr:
+			ft, repetition = ft.Elem(), parquetOptional
+		case reflect.Interface, reflect.Map, reflect.Slice:
+			repetition = parquetOptional
+		}
+
+		var node *parquetNode
+		if ft.Kind() == reflect.Struct && ft != timeType && !ft.Implements(textMarshalerType) {
+			children, err := parquetFields(ft, seen)
+			if err != nil {
+				return nil, err
+			}
+			if len(children) == 0 {
+				continue
+			}
+			node = &parquetNode{children: children}
+		} else {
+			node = parquetLeaf(ft)
+		}
+		node.name, node.repetition, node.field = key, repetition, i
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// parquetLeaf picks the physical and logical type for a Go type.
+func parquetLeaf(t reflect.Type) *parquetNode {
+	n := &parquetNode{converted: -1}
+	integer := func(physical, converted, bits int, signed bool) {
+		n.physical, n.converted = physical, converted
+		n.logical = thriftFields{{10, thriftFields{{1, int8(bits)}, {2, signed}}}}
+		if !signed {
+			n.kind = parquetKindUnsigned
+		}
+	}
+
+	switch {
+	case t == timeType:
+		n.physical, n.converted, n.kind = parquetInt64, parquetTimestampMicros, parquetKindMicros
+		n.logical = thriftFields{{8, thriftFields{{1, true}, {2, thriftFields{{2, thriftFields{}}}}}}}
+	case t.Kind() == reflect.Bool:
+		n.physical = parquetBoolean
+	case t.Kind() == reflect.Int8:
+		integer(parquetInt32, parquetInt8, 8, true)
+	case t.Kind() == reflect.Int16:
+		integer(parquetInt32, parquetInt16, 16, true)
+	case t.Kind() == reflect.Int32:
+		n.physical = parquetInt32
+	case t.Kind() == reflect.Int || t.Kind() == reflect.Int64:
+		n.physical = parquetInt64
+	case t.Kind() == reflect.Uint8:
+		integer(parquetInt32, parquetUint8, 8, false)
+	case t.Kind() == reflect.Uint16:
+		integer(parquetInt32, parquetUint16, 16, false)
+	case t.Kind() == reflect.Uint32:
+		integer(parquetInt32, parquetUint32, 32, false)
+	case t.Kind() == reflect.Uint || t.Kind() == reflect.Uint64 || t.Kind() == reflect.Uintptr:
+		integer(parquetInt64, parquetUint64, 64, false)
+	case t.Kind() == reflect.Float32:
+		n.physical = parquetFloat
+	case t.Kind() == reflect.Float64:
+		n.physical = parquetDouble
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		n.physical = parquetByteArray
+	default:
+		n.physical, n.converted, n.kind = parquetByteArray, parquetUTF8, parquetKindString
+		n.logical = thriftFields{{1, thriftFields{}}}
+		n.format = t.Kind() != reflect.String
+	}
+	return n
+}
+
+func parquetSchemaElements(root *parquetNode) []thriftFields {
+	elements := []thriftFields{{{4, root.name}, {5, int32(len(root.children))}}}
+	var walk func(n *parquetNode)
+	walk = func(n *parquetNode) {
+		if n.children != nil {
+			elements = append(elements, thriftFields{
+				{3, int32(n.repetition)},
+				{4, n.name},
+				{5, int32(len(n.children))},
+			})
+			for _, child := range n.children {
+				walk(child)
+			}
+			return
+		}
+		el := thriftFields{{1, int32(n.physical)}, {3, int32(n.repetition)}, {4, n.name}}
+		if n.converted >= 0 {
+			el = append(el, thriftField{6, int32(n.converted)})
+		}
+		if n.logical != nil {
+			el = append(el, thriftField{10, n.logical})
+		}
+		elements = append(elements, el)
+	}
+	for _, child := range root.children {
+		walk(child)
+	}
+	return elements
+}
+
+// parquetLeafValue returns the physical value of a column for one row, or
+// nil, and its definition level.
+func parquetLeafValue(item any, col *parquetColumn) (any, int) {
+	v := reflect.ValueOf(item)
+	switch v.Kind() {
+	case reflect.Map:
+		v = reflect.ValueOf(getFieldValue(item, col.leaf().name))
+	case reflect.Ptr:
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+	}
+
+	def := 0
+	for _, n := range col.nodes {
+		if v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Struct && n.field >= 0 {
+			v = v.Field(n.field)
+		}
+		if n.repetition == parquetOptional {
+			if !v.IsValid() || isNil(v.Interface()) {
+				return nil, def
+			}
+			def++
+		}
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return parquetPhysical(col.leaf(), v), def
+}
+
+func parquetPhysical(n *parquetNode, v reflect.Value) any {
+	if n.format {
+		return []byte(formatCell(v.Interface(), defaultCSVConfig()))
+	}
+
+	switch n.physical {
+	case parquetBoolean:
+		return v.Bool()
+	case parquetInt32:
+		if v.CanUint() {
+			return int32(uint32(v.Uint()))
+		}
+		return int32(parquetInt(v))
+	case parquetInt64:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t.UnixMicro()
+		}
+		if v.CanUint() {
+			return int64(v.Uint())
+		}
+		return parquetInt(v)
+	case parquetFloat:
+		return float32(parquetFloat64(v))
+	case parquetDouble:
+		return parquetFloat64(v)
+	}
+	if v.Kind() == reflect.String {
+		return []byte(v.String())
+	}
+	return v.Bytes()
+}
+
+func parquetInt(v reflect.Value) int64 {
+	switch {
+	case v.CanInt():
+		return v.Int()
+	case v.CanUint():
+		return int64(v.Uint())
+	case v.CanFloat():
+		return int64(v.Float())
+	}
+	return 0
+}
+
+func parquetFloat64(v reflect.Value) float64 {
+	if v.CanFloat() {
+		return v.Float()
+	}
+	return float64(parquetInt(v))
+}
+
+// writeParquetChunk writes one column chunk (an optional dictionary page
+// and a single data page) and returns its ColumnChunk metadata and
+// uncompressed size.
+func writeParquetChunk(cw *countingWriter, col *parquetColumn, values []any, defs []int, config *ParquetConfig) (thriftFields, int64) {
+	leaf := col.leaf()
+	present := make([]any, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			present = append(present, v)
+		}
+	}
+
+	var dict []any
+	var indices []int
+	if config.dictionary && leaf.physical != parquetBoolean {
+		dict, indices = parquetDictionary(present)
+	}
+
+	start := cw.pos
+	var uncompressed, dictOffset int64
+	writePage := func(header thriftFields, body []byte) {
+		compressed := parquetCompress(config.codec, body)
+		header = append(thriftFields{
+			{1, header[0].value},
+			{2, int32(len(body))},
+			{3, int32(len(compressed))},
+		}, header[1:]...)
+		raw := header.encode()
+		cw.write(raw)
+		cw.write(compressed)
+		uncompressed += int64(len(raw) + len(body))
+	}
+
+	encodings := []int32{parquetPlain, parquetRLE}
+	var body []byte
+	if col.maxDef > 0 {
+		levels := appendRLE(nil, defs, bitsFor(col.maxDef))
+		body = binary.LittleEndian.AppendUint32(body, uint32(len(levels)))
+		body = append(body, levels...)
+	}
+
+	encoding := int32(parquetPlain)
+	if dict != nil {
+		dictOffset = cw.pos
+		writePage(thriftFields{
+			{1, int32(parquetDictionaryPage)},
+			{7, thriftFields{{1, int32(len(dict))}, {2, int32(parquetPlain)}}},
+		}, appendParquetPlain(nil, leaf.physical, dict))
+
+		width := max(bitsFor(len(dict)-1), 1)
+		body = append(body, byte(width))
+		body = appendRLE(body, indices, width)
+		encoding = parquetRLEDictionary
+		encodings = append(encodings, parquetRLEDictionary)
+	} else {
+		body = appendParquetPlain(body, leaf.physical, present)
+	}
+
+	dataOffset := cw.pos
+	writePage(thriftFields{
+		{1, int32(parquetDataPage)},
+		{5, thriftFields{
+			{1, int32(len(values))},
+			{2, encoding},
+			{3, int32(parquetRLE)},
+			{4, int32(parquetRLE)},
+		}},
+	}, body)
+
+	meta := thriftFields{
+		{1, int32(leaf.physical)},
+		{2, encodings},
+		{3, col.path()},
+		{4, int32(config.codec)},
+		{5, int64(len(values))},
+		{6, uncompressed},
+		{7, cw.pos - start},
+		{9, dataOffset},
+	}
+	if dict != nil {
+		meta = append(meta, thriftField{11, dictOffset})
+	}
+	return thriftFields{{2, start}, {3, meta}}, uncompressed
+}
+
+// parquetDictionary returns the distinct values and the index of each
+// value, or nil when a dictionary would not pay off.
+func parquetDictionary(values []any) ([]any, []int) {
+	seen := make(map[any]int)
+	dict := make([]any, 0)
+	indices := make([]int, len(values))
+	for i, v := range values {
+		key := v
+		if b, ok := v.([]byte); ok {
+			key = string(b)
+		}
+		idx, ok := seen[key]
+		if !ok {
+			if len(dict) == parquetMaxDictionary {
+				return nil, nil
+			}
+			idx = len(dict)
+			seen[key] = idx
+			dict = append(dict, v)
+		}
+		indices[i] = idx
+	}
+	if len(dict) == 0 || len(dict) >= len(values) {
+		return nil, nil
+	}
+	return dict, indices
+}
+
+func parquetCompress(codec ParquetCodec, data []byte) []byte {
+	switch codec {
+	case ParquetSnappy:
+		return snappyEncode(data)
+	case ParquetGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		return buf.Bytes()
+	}
+	return data
+}
+
+func appendParquetPlain(buf []byte, physical int, values []any) []byte {
+	if physical == parquetBoolean {
+		packed := make([]byte, (len(values)+7)/8)
+		for i, v := range values {
+			if v.(bool) {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		return append(buf, packed...)
+	}
+	for _, v := range values {
+		switch v := v.(type) {
+		case int32:
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(v))
+		case int64:
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+		case float32:
+			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v))
+		case float64:
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+		case []byte:
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+			buf = append(buf, v...)
+		}
+	}
+	return buf
+}
+
+func bitsFor(n int) int {
+	bits := 0
+	for n > 0 {
+		bits++
+		n >>= 1
+	}
+	return bits
+}
+
+// appendRLE encodes values with the RLE/bit-packing hybrid: runs of 8 or
+// more equal values are run-length encoded, everything else is bit-packed
+// in groups of 8.
+func appendRLE(buf []byte, values []int, width int) []byte {
+	runLength := func(i int) int {
+		n := 1
+		for i+n < len(values) && values[i+n] == values[i] {
+			n++
+		}
+		return n
+	}
+
+	for i := 0; i < len(values); {
+		if n := runLength(i); n >= 8 {
+			buf = binary.AppendUvarint(buf, uint64(n)<<1)
+			for b := 0; b < (width+7)/8; b++ {
+				buf = append(buf, byte(values[i]>>(8*b)))
+			}
+			i += n
+			continue
+		}
+
+		start := i
+		for i < len(values) && runLength(i) < 8 {
+			i += 8
+		}
+		i = min(i, len(values))
+		groups := (i - start + 7) / 8
+		buf = binary.AppendUvarint(buf, uint64(groups)<<1|1)
+
+		packed := make([]byte, groups*width)
+		for k, v := range values[start:i] {
+			for b := 0; b < width; b++ {
+				if v>>b&1 != 0 {
+					bit := k*width + b
+					packed[bit/8] |= 1 << (bit % 8)
+				}
+			}
+		}
+		buf = append(buf, packed...)
+	}
+	return buf
+}
+
+// decodeRLE decodes n values of the RLE/bit-packing hybrid.
+func decodeRLE(data []byte, width, n int) []int {
+	if width > 32 {
+		panic(errParquet)
+	}
+	values := make([]int, 0, min(n, 8*len(data)+8))
+	pos := 0
+	for len(values) < n {
+		header, k := binary.Uvarint(data[pos:])
+		if k <= 0 {
+			panic(errParquet)
+		}
+		pos += k
+
+		if header&1 == 0 {
+			size := (width + 7) / 8
+			if pos+size > len(data) {
+				panic(errParquet)
+			}
+			v := 0
+			for b := 0; b < size; b++ {
+				v |= int(data[pos+b]) << (8 * b)
+			}
+			pos += size
+			for count := min(int(header>>1), n-len(values)); count > 0; count-- {
+				values = append(values, v)
+			}
+			continue
+		}
+
+		if header>>1 > uint64(len(data)) {
+			panic(errParquet)
+		}
+		count := int(header>>1) * 8
+		if count == 0 || count*width > 8*(len(data)-pos) {
+			panic(errParquet)
+		}
+		for k := 0; k < count && len(values) < n; k++ {
+			v := 0
+			for b := 0; b < width; b++ {
+				bit := k*width + b
+				v |= int(data[pos+bit/8]>>(bit%8)&1) << b
+			}
+			values = append(values, v)
+		}
+		pos += count * width / 8
+	}
+	return values
+}
+
+// FromParquet reads a Parquet file into a pipeline of structs, struct
+// pointers or map[string]any. Columns are matched to struct fields like
+// FromCSV headers, level by level for nested groups; a
+// pointer-to-struct field is allocated only when its group is present. Map
+// pipelines get one key per leaf column, named by its dotted path.
+//
+// The reader needs the file size, so r must be an *os.File, *bytes.Reader,
+// *io.SectionReader or anything else with a Size or Stat method. Plain and
+// dictionary encodings with snappy, gzip or no compression are supported;
+// repeated fields are not.
+func FromParquet[T any](r io.ReaderAt) (*Pipeline[T], error) {
+	var zero T
+	typ := reflect.TypeOf(zero)
+	structType, err := recordType(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, values, defs, rows, err := readParquet(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]T, rows)
+	resultIdx := make([]int, rows)
+	for i := range resultIdx {
+		resultIdx[i] = i + 1
+	}
+
+	if structType != nil {
+		records := make([]reflect.Value, rows)
+		for i := range records {
+			records[i] = recordValue(&result[i])
+		}
+		for j, col := range columns {
+			path := parquetFieldPath(structType, col)
+			if path == nil {
+				continue
+			}
+			for i := range result {
+				def := col.maxDef
+				if defs[j] != nil {
+					def = defs[j][i]
+				}
+				if err := setParquetValue(records[i], col, path, def, values[j][i]); err != nil {
+					return nil, fmt.Errorf("row %d: field %s: %w", i+1, strings.Join(col.path(), "."), err)
+				}
+			}
+		}
+		return &Pipeline[T]{data: result, originalIndex: resultIdx}, nil
+	}
+
+	names := make([]string, len(columns))
+	for j, col := range columns {
+		names[j] = strings.Join(col.path(), ".")
+	}
+	for i := range result {
+		if m, ok := any(&result[i]).(*map[string]any); ok {
+			*m = make(map[string]any, len(names))
+			for j, name := range names {
+				(*m)[name] = values[j][i]
+			}
+		}
+	}
+
+	p := &Pipeline[T]{data: result, originalIndex: resultIdx}
+	if typ != nil && typ.Kind() == reflect.Map {
+		p.columns = names
+	}
+	return p, nil
+}
+
+// parquetFieldPath resolves a column to struct field indices, or nil when
+// some level has no matching field.
+func parquetFieldPath(typ reflect.Type, col *parquetColumn) []int {
+	path := make([]int, len(col.nodes))
+	for k, n := range col.nodes {
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return nil
+		}
+		targets := matchColumns(typ, []string{n.name})
+		if len(targets) == 0 {
+			return nil
+		}
+		path[k] = targets[0].field
+		typ = typ.Field(path[k]).Type
+	}
+	return path
+}
+
+func setParquetValue(v reflect.Value, col *parquetColumn, path []int, def int, val any) error {
+	for k, idx := range path {
+		if def < col.levels[k] {
+			return nil
+		}
+		v = v.Field(idx)
+		if k == len(path)-1 {
+			break
+		}
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+	}
+	return setFromValue(v, val)
+}
+
+func readerSize(r io.ReaderAt) (int64, error) {
+	switch s := r.(type) {
+	case interface{ Size() int64 }:
+		return s.Size(), nil
+	case interface{ Stat() (fs.FileInfo, error) }:
+		info, err := s.Stat()
+		if err != nil {
+			return 0, err
+		}
+		return info.Size(), nil
+	}
+	return 0, errors.New("cannot determine the Parquet file size: reader has no Size or Stat method")
+}
+
+// readParquet decodes every column of a Parquet file. values[j] holds one
+// value per row (nil for nulls) and defs[j] the definition levels of
+// optional columns.
+func readParquet(r io.ReaderAt) (columns []*parquetColumn, values [][]any, defs [][]int, rows int, err error) {
+	size, err := readerSize(r)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+	tail := make([]byte, 8)
+	if size < 12 {
+		return nil, nil, nil, 0, errors.New("not a Parquet file")
+	}
+	if _, err := r.ReadAt(tail, size-8); err != nil {
+		return nil, nil, nil, 0, err
+	}
+	if !bytes.Equal(tail[4:], parquetMagic) {
+		return nil, nil, nil, 0, errors.New("not a Parquet file")
+	}
+	metaLen := int64(binary.LittleEndian.Uint32(tail))
+	if metaLen > size-12 {
+		return nil, nil, nil, 0, errParquet
+	}
+	raw := make([]byte, metaLen)
+	if _, err := r.ReadAt(raw, size-8-metaLen); err != nil {
+		return nil, nil, nil, 0, err
+	}
+	defer recoverFormat(&err)
+
+	meta, _ := readThriftStruct(raw)
+	elements := meta.list(2)
+	if len(elements) == 0 {
+		return nil, nil, nil, 0, errParquet
+	}
+	pos := 0
+	root := parseParquetNode(elements, &pos)
+	columns, err = parquetColumns(root)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	values = make([][]any, len(columns))
+	defs = make([][]int, len(columns))
+	for _, g := range meta.list(4) {
+		group, _ := g.(thriftValues)
+		n := int(group.int(3))
+		chunks := group.list(1)
+		if n < 0 || n > math.MaxInt32 || len(chunks) != len(columns) {
+			return nil, nil, nil, 0, errParquet
+		}
+		for j, c := range chunks {
+			chunk, _ := c.(thriftValues)
+			v, d, err := readParquetChunk(r, size, columns[j], chunk.strct(3), n)
+			if err != nil {
+				return nil, nil, nil, 0, fmt.Errorf("column %s: %w", strings.Join(columns[j].path(), "."), err)
+			}
+			values[j] = append(values[j], v...)
+			if d != nil {
+				defs[j] = append(defs[j], d...)
+			}
+		}
+		rows += n
+	}
+	for j := range values {
+		if values[j] == nil {
+			values[j] = make([]any, rows)
+		}
+	}
+	return columns, values, defs, rows, nil
+}
+
+func parseParquetNode(elements []any, pos *int) *parquetNode {
+	if *pos >= len(elements) {
+		panic(errParquet)
+	}
+	el, _ := elements[*pos].(thriftValues)
+	*pos++
+
+	n := &parquetNode{
+		name:       el.str(4),
+		repetition: int(el.int(3)),
+		physical:   int(el.int(1)),
+		typeLength: int(el.int(2)),
+		converted:  -1,
+		field:      -1,
+	}
+	if el.has(5) {
+		n.children = make([]*parquetNode, 0)
+		for i := int64(0); i < el.int(5); i++ {
+			n.children = append(n.children, parseParquetNode(elements, pos))
+		}
+		return n
+	}
+	if el.has(6) {
+		n.converted = int(el.int(6))
+	}
+	logical := el.strct(10)
+	n.kind = parquetKindOf(n.converted, logical)
+	if n.kind == parquetKindDecimal {
+		n.scale = int(el.int(7))
+		if logical.has(5) {
+			n.scale = int(logical.strct(5).int(1))
+		}
+		if n.scale < 0 || n.scale > 38 {
+			panic(errParquet)
+		}
+	}
+	return n
+}
+
+func parquetKindOf(converted int, logical thriftValues) parquetKind {
+	switch {
+	case logical.has(1), logical.has(4), logical.has(12):
+		return parquetKindString
+	case logical.has(5):
+		return parquetKindDecimal
+	case logical.has(6):
+		return parquetKindDate
+	case logical.has(8):
+		unit := logical.strct(8).strct(2)
+		switch {
+		case unit.has(1):
+			return parquetKindMillis
+		case unit.has(3):
+			return parquetKindNanos
+		}
+		return parquetKindMicros
+	case logical.has(10):
+		if signed, _ := logical.strct(10)[2].(bool); !signed {
+			return parquetKindUnsigned
+		}
+		return parquetKindPlain
+	}
+
+	switch converted {
+	case parquetUTF8, parquetEnum, parquetJSON:
+		return parquetKindString
+	case parquetDecimal:
+		return parquetKindDecimal
+	case parquetDate:
+		return parquetKindDate
+	case parquetTimestampMillis:
+		return parquetKindMillis
+	case parquetTimestampMicros:
+		return parquetKindMicros
+	case parquetUint8, parquetUint16, parquetUint32, parquetUint64:
+		return parquetKindUnsigned
+	}
+	return parquetKindPlain
+}
+
+func readParquetChunk(r io.ReaderAt, size int64, col *parquetColumn, meta thriftValues, rows int) ([]any, []int, error) {
+	start := meta.int(9)
+	if off := meta.int(11); meta.has(11) && off > 0 && off < start {
+		start = off
+	}
+	length := meta.int(7)
+	if start < 4 || length < 0 || start+length > size {
+		return nil, nil, errParquet
+	}
+	buf := make([]byte, length)
+	if _, err := r.ReadAt(buf, start); err != nil {
+		return nil, nil, err
+	}
+
+	leaf := col.leaf()
+	codec := meta.int(4)
+	values := make([]any, 0, min(rows, len(buf)))
+	var defs []int
+	if col.maxDef > 0 {
+		defs = make([]int, 0, cap(values))
+	}
+	var dict []any
+
+	for pos := 0; pos < len(buf) && len(values) < rows; {
+		header, n := readThriftStruct(buf[pos:])
+		pos += n
+		compressedSize, uncompressedSize := int(header.int(3)), int(header.int(2))
+		if compressedSize < 0 || compressedSize > len(buf)-pos || uncompressedSize < 0 {
+			return nil, nil, errParquet
+		}
+		page := buf[pos : pos+compressedSize]
+		pos += compressedSize
+
+		switch header.int(1) {
+		case parquetDictionaryPage:
+			dh := header.strct(7)
+			if enc := dh.int(2); enc != parquetPlain && enc != parquetPlainDictionary {
+				return nil, nil, fmt.Errorf("unsupported dictionary encoding %d", enc)
+			}
+			data, err := parquetDecompress(codec, page, uncompressedSize)
+			if err != nil {
+				return nil, nil, err
+			}
+			dict = decodeParquetPlain(leaf, data, int(dh.int(1)))
+
+		case parquetDataPage, parquetDataPageV2:
+			var count int
+			var levels, data []byte
+			var encoding int64
+			if header.int(1) == parquetDataPage {
+				dh := header.strct(5)
+				count, encoding = int(dh.int(1)), dh.int(2)
+				body, err := parquetDecompress(codec, page, uncompressedSize)
+				if err != nil {
+					return nil, nil, err
+				}
+				if col.maxDef > 0 {
+					if dh.int(3) != parquetRLE || len(body) < 4 {
+						return nil, nil, fmt.Errorf("unsupported definition level encoding %d", dh.int(3))
+					}
+					n := int(binary.LittleEndian.Uint32(body))
+					if n > len(body)-4 {
+						return nil, nil, errParquet
+					}
+					levels, body = body[4:4+n], body[4+n:]
+				}
+				data = body
+			} else {
+				dh := header.strct(8)
+				count, encoding = int(dh.int(1)), dh.int(4)
+				repLen, defLen := int(dh.int(6)), int(dh.int(5))
+				if repLen < 0 || defLen < 0 || repLen+defLen > len(page) {
+					return nil, nil, errParquet
+				}
+				levels, data = page[repLen:repLen+defLen], page[repLen+defLen:]
+				if compressed, ok := dh[7].(bool); !ok || compressed {
+					var err error
+					if data, err = parquetDecompress(codec, data, uncompressedSize-repLen-defLen); err != nil {
+						return nil, nil, err
+					}
+				}
+			}
+			if count < 0 || count > rows-len(values) {
+				return nil, nil, errParquet
+			}
+
+			present := count
+			var pageDefs []int
+			if col.maxDef > 0 {
+				pageDefs = decodeRLE(levels, bitsFor(col.maxDef), count)
+				present = 0
+				for _, d := range pageDefs {
+					if d > col.maxDef {
+						return nil, nil, errParquet
+					}
+					if d == col.maxDef {
+						present++
+					}
+				}
+				defs = append(defs, pageDefs...)
+			}
+
+			decoded, err := decodeParquetValues(leaf, int(encoding), data, present, dict)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i := 0; i < count; i++ {
+				if pageDefs != nil && pageDefs[i] < col.maxDef {
+					values = append(values, nil)
+				} else {
+					values = append(values, decoded[0])
+					decoded = decoded[1:]
+				}
+			}
+		}
+	}
+
+	if len(values) != rows {
+		return nil, nil, errParquet
+	}
+	return values, defs, nil
+}
+
+func parquetDecompress(codec int64, data []byte, size int) ([]byte, error) {
+	switch ParquetCodec(codec) {
+	case ParquetUncompressed:
+		return data, nil
+	case ParquetSnappy:
+		return snappyDecode(data)
+	case ParquetGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(io.LimitReader(zr, int64(size)))
+	}
+	return nil, fmt.Errorf("unsupported compression codec %d", codec)
+}
+
+func decodeParquetValues(leaf *parquetNode, encoding int, data []byte, n int, dict []any) ([]any, error) {
+	switch encoding {
+	case parquetPlain:
+		return decodeParquetPlain(leaf, data, n), nil
+	case parquetPlainDictionary, parquetRLEDictionary:
+		if n == 0 {
+			return nil, nil
+		}
+		if dict == nil || len(data) == 0 {
+			return nil, errParquet
+		}
+		indices := decodeRLE(data[1:], int(data[0]), n)
+		values := make([]any, n)
+		for i, idx := range indices {
+			if idx >= len(dict) {
+				return nil, errParquet
+			}
+			values[i] = dict[idx]
+		}
+		return values, nil
+	case parquetRLE:
+		if leaf.physical != parquetBoolean || len(data) < 4 {
+			break
+		}
+		bits := decodeRLE(data[4:], 1, n)
+		values := make([]any, n)
+		for i, b := range bits {
+			values[i] = b == 1
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %d", encoding)
+}
+
+func decodeParquetPlain(leaf *parquetNode, data []byte, n int) []any {
+	width := map[int]int{parquetInt32: 4, parquetInt64: 8, parquetInt96: 12, parquetFloat: 4, parquetDouble: 8, parquetByteArray: 4}[leaf.physical]
+	switch leaf.physical {
+	case parquetBoolean:
+		width = 1
+		if n > 8*len(data) {
+			panic(errParquet)
+		}
+	case parquetFixed:
+		width = leaf.typeLength
+	}
+	if n < 0 || width <= 0 || (leaf.physical != parquetBoolean && n > len(data)/width) {
+		panic(errParquet)
+	}
+
+	values := make([]any, n)
+	pos := 0
+	for i := range values {
+		var v any
+		switch leaf.physical {
+		case parquetBoolean:
+			v = data[i/8]>>(i%8)&1 == 1
+		case parquetInt32:
+			v = int32(binary.LittleEndian.Uint32(data[pos:]))
+		case parquetInt64:
+			v = int64(binary.LittleEndian.Uint64(data[pos:]))
+		case parquetInt96:
+			nanos := int64(binary.LittleEndian.Uint64(data[pos:]))
+			day := int64(binary.LittleEndian.Uint32(data[pos+8:]))
+			v = time.Unix((day-2440588)*86400, nanos).UTC()
+		case parquetFloat:
+			v = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[pos:])))
+		case parquetDouble:
+			v = math.Float64frombits(binary.LittleEndian.Uint64(data[pos:]))
+		case parquetByteArray:
+			if pos+4 > len(data) {
+				panic(errParquet)
+			}
+			size := int(binary.LittleEndian.Uint32(data[pos:]))
+			if size > len(data)-pos-4 {
+				panic(errParquet)
+			}
+			v = data[pos+4 : pos+4+size]
+			pos += size
+		case parquetFixed:
+			v = data[pos : pos+width]
+		}
+		if leaf.physical != parquetBoolean {
+			pos += width
+		}
+		values[i] = leaf.convert(v)
+	}
+	return values
+}
+
+// convert maps a decoded physical value to its Go value: int64 for
+// integers (uint64 for unsigned 64-bit), float64 for floats and decimals,
+// bool, string for text, []byte for other binaries and time.Time for dates
+// and timestamps.
+func (n *parquetNode) convert(v any) any {
+	if n.kind == parquetKindDecimal {
+		return parquetDecimalValue(v, n.scale)
+	}
+
+	switch v := v.(type) {
+	case int32:
+		switch n.kind {
+		case parquetKindUnsigned:
+			return int64(uint32(v))
+		case parquetKindDate:
+			return time.Unix(int64(v)*86400, 0).UTC()
+		}
+		return int64(v)
+	case int64:
+		switch n.kind {
+		case parquetKindUnsigned:
+			return uint64(v)
+		case parquetKindMillis:
+			return time.UnixMilli(v).UTC()
+		case parquetKindMicros:
+			return time.UnixMicro(v).UTC()
+		case parquetKindNanos:
+			return time.Unix(0, v).UTC()
+		}
+		return v
+	case []byte:
+		if n.kind == parquetKindString {
+			return string(v)
+		}
+		return bytes.Clone(v)
+	}
+	return v
+}
+
+// parquetDecimalValue converts an unscaled decimal, stored as an integer or
+// as big-endian two's complement bytes, to a float64.
+func parquetDecimalValue(v any, scale int) any {
+	var unscaled float64
+	switch v := v.(type) {
+	case int32:
+		unscaled = float64(v)
+	case int64:
+		unscaled = float64(v)
+	case []byte:
+		x := new(big.Int).SetBytes(v)
+		if len(v) > 0 && v[0]&0x80 != 0 {
+			x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(8*len(v))))
+		}
+		unscaled, _ = new(big.Float).SetInt(x).Float64()
+	default:
+		return v
+	}
+	return unscaled / math.Pow10(scale)
+}
@@ -0,0 +1,555 @@
+package plygo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ParquetAddress struct {
+	City string `plygo:"city"`
+	Zip  *string
+}
+
+type ParquetOrder struct {
+	ID       int64
+	Customer string `plygo:"customer"`
+	Qty      uint8
+	Price    float32
+	Paid     bool
+	Placed   time.Time
+	Latency  time.Duration
+	Note     *string
+	Raw      []byte
+	Ship     ParquetAddress
+	Billing  *ParquetAddress
+	Internal string `plygo:"-"`
+}
+
+func parquetOrders() []ParquetOrder {
+	zip, note := "10115", "gift"
+	placed := time.Date(2024, 3, 5, 9, 30, 0, 123456000, time.UTC)
+	return []ParquetOrder{
+		{1, "Alice", 3, 9.5, true, placed, time.Second, &note, []byte{1, 2}, ParquetAddress{"Berlin", &zip}, nil, "x"},
+		{2, "Bob", 1, 20, false, placed.Add(time.Hour), 0, nil, nil, ParquetAddress{"Paris", nil}, &ParquetAddress{"Lyon", nil}, "y"},
+		{3, "Alice", 3, 9.5, true, placed, time.Millisecond, nil, []byte{}, ParquetAddress{"Berlin", &zip}, &ParquetAddress{"Nice", &zip}, "z"},
+	}
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	options := map[string][]ParquetOption{
+		"default":      nil,
+		"uncompressed": {ParquetCompression(ParquetUncompressed), ParquetDictionary(false)},
+		"gzip":         {ParquetCompression(ParquetGzip), ParquetRowGroupSize(2)},
+	}
+
+	for name, opts := range options {
+		orders := parquetOrders()
+		var buf bytes.Buffer
+		if err := From(orders).WriteParquet(&buf, opts...); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		data := buf.Bytes()
+		if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+			t.Fatalf("%s: expected Parquet magic at both ends", name)
+		}
+
+		p, err := FromParquet[ParquetOrder](bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		for i := range orders {
+			orders[i].Internal = ""
+		}
+		if !reflect.DeepEqual(p.Collect(), orders) {
+			t.Errorf("%s: round trip mismatch:\n%+v\n%+v", name, orders, p.Collect())
+		}
+		if !reflect.DeepEqual(p.Which(), []int{1, 2, 3}) {
+			t.Errorf("%s: expected indices [1 2 3], got %v", name, p.Which())
+		}
+	}
+}
+
+func TestParquetPointersAndUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	if err := From(parquetOrders()).WriteParquet(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	p, err := FromParquet[*ParquetOrder](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	orders := parquetOrders()
+	for i := range orders {
+		orders[i].Internal = ""
+	}
+	got := p.Collect()
+	if len(got) != len(orders) {
+		t.Fatalf("Expected %d orders, got %d", len(orders), len(got))
+	}
+	for i := range got {
+		if got[i] == nil || !reflect.DeepEqual(*got[i], orders[i]) {
+			t.Errorf("Expected order %d to be %+v, got %+v", i, orders[i], got[i])
+		}
+	}
+
+	if _, err := FromParquet[[]string](bytes.NewReader(buf.Bytes())); err == nil || !strings.Contains(err.Error(), "cannot read rows into []string") {
+		t.Errorf("Expected unsupported type error, got %v", err)
+	}
+}
+
+func TestParquetSchemaAndEncodings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := From(parquetOrders()).WriteParquet(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	columns, _, _, rows, err := readParquet(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rows != 3 {
+		t.Errorf("Expected 3 rows, got %d", rows)
+	}
+
+	paths := make([]string, len(columns))
+	for i, col := range columns {
+		paths[i] = strings.Join(col.path(), ".")
+	}
+	expected := []string{"ID", "customer", "Qty", "Price", "Paid", "Placed", "Latency", "Note", "Raw",
+		"Ship.city", "Ship.Zip", "Billing.city", "Billing.Zip"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected columns %v, got %v", expected, paths)
+	}
+	if columns[12].maxDef != 2 || !reflect.DeepEqual(columns[12].levels, []int{1, 2}) {
+		t.Errorf("Expected Billing.Zip levels [1 2], got %v", columns[12].levels)
+	}
+	if leaf := columns[5].leaf(); leaf.physical != parquetInt64 || leaf.kind != parquetKindMicros {
+		t.Errorf("Expected microsecond timestamps, got %+v", leaf)
+	}
+
+	size := int64(buf.Len())
+	meta, _ := readThriftStruct(buf.Bytes()[size-8-int64(binary.LittleEndian.Uint32(buf.Bytes()[size-8:])) : size-8])
+	chunks := meta.list(4)[0].(thriftValues).list(1)
+	customer := chunks[1].(thriftValues).strct(3)
+	if !customer.has(11) || customer.int(4) != int64(ParquetSnappy) {
+		t.Errorf("Expected a snappy dictionary-encoded customer column, got %v", customer)
+	}
+	if id := chunks[0].(thriftValues).strct(3); id.has(11) {
+		t.Error("Expected no dictionary for unique IDs")
+	}
+}
+
+func TestParquetMaps(t *testing.T) {
+	records := FromRecords([]map[string]any{
+		{"id": 1, "score": 1.5, "tag": "a"},
+		{"id": 2, "score": 2, "tag": nil},
+	})
+
+	var buf bytes.Buffer
+	if err := records.WriteParquet(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	p, err := FromParquet[map[string]any](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []map[string]any{
+		{"id": int64(1), "score": 1.5, "tag": "a"},
+		{"id": int64(2), "score": 2.0, "tag": nil},
+	}
+	if !reflect.DeepEqual(p.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, p.Collect())
+	}
+	if !reflect.DeepEqual(p.FieldNames(), []string{"id", "score", "tag"}) {
+		t.Errorf("Unexpected columns %v", p.FieldNames())
+	}
+
+	buf.Reset()
+	if err := From(parquetOrders()).WriteParquet(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	nested, err := FromParquet[map[string]any](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if row := nested.Collect()[0]; row["Ship.city"] != "Berlin" || row["Billing.city"] != nil || row["Qty"] != int64(3) {
+		t.Errorf("Unexpected nested row %v", row)
+	}
+}
+
+// TestParquetDataPageV2 reads a hand-built file with a v2 data page holding
+// an optional DATE column.
+func TestParquetDataPageV2(t *testing.T) {
+	levels := appendRLE(nil, []int{1, 0, 1}, 1)
+	values := binary.LittleEndian.AppendUint32(nil, 19787)
+	values = binary.LittleEndian.AppendUint32(values, 0)
+
+	header := thriftFields{
+		{1, int32(parquetDataPageV2)},
+		{2, int32(len(levels) + len(values))},
+		{3, int32(len(levels) + len(values))},
+		{8, thriftFields{
+			{1, int32(3)}, {2, int32(1)}, {3, int32(3)}, {4, int32(parquetPlain)},
+			{5, int32(len(levels))}, {6, int32(0)}, {7, false},
+		}},
+	}.encode()
+	chunk := append(append(header, levels...), values...)
+
+	meta := thriftFields{
+		{1, int32(1)},
+		{2, []thriftFields{
+			{{4, "schema"}, {5, int32(1)}},
+			{{1, int32(parquetInt32)}, {3, int32(parquetOptional)}, {4, "day"}, {6, int32(parquetDate)}},
+		}},
+		{3, int64(3)},
+		{4, []thriftFields{{
+			{1, []thriftFields{{{2, int64(4)}, {3, thriftFields{
+				{1, int32(parquetInt32)},
+				{2, []int32{parquetPlain, parquetRLE}},
+				{3, []string{"day"}},
+				{4, int32(0)},
+				{5, int64(3)},
+				{6, int64(len(chunk))},
+				{7, int64(len(chunk))},
+				{9, int64(4)},
+			}}}}},
+			{2, int64(len(chunk))},
+			{3, int64(3)},
+		}}},
+	}.encode()
+
+	file := append([]byte("PAR1"), chunk...)
+	file = append(file, meta...)
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(meta)))
+	file = append(file, "PAR1"...)
+
+	type Day struct {
+		Day *time.Time
+	}
+	p, err := FromParquet[Day](bytes.NewReader(file))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	days := p.Collect()
+	if len(days) != 3 || days[1].Day != nil || !days[2].Day.Equal(time.Unix(0, 0)) {
+		t.Fatalf("Unexpected days %+v", days)
+	}
+	if want := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC); !days[0].Day.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, days[0].Day)
+	}
+}
+
+func TestParquetErrors(t *testing.T) {
+	if _, err := FromParquet[ParquetOrder](bytes.NewReader([]byte("not parquet at all"))); err == nil {
+		t.Error("Expected error for missing magic")
+	}
+
+	var buf bytes.Buffer
+	if err := From(parquetOrders()).WriteParquet(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	corrupt := append([]byte(nil), buf.Bytes()...)
+	binary.LittleEndian.PutUint32(corrupt[len(corrupt)-8:], 1<<30)
+	if _, err := FromParquet[ParquetOrder](bytes.NewReader(corrupt)); err == nil {
+		t.Error("Expected error for corrupt footer length")
+	}
+	corrupt = append([]byte(nil), buf.Bytes()...)
+	for i := 4; i < 200; i++ {
+		corrupt[i] ^= 0xff
+	}
+	if _, err := FromParquet[ParquetOrder](bytes.NewReader(corrupt)); err == nil {
+		t.Error("Expected error for corrupt pages")
+	}
+
+	meta := thriftFields{
+		{1, int32(1)},
+		{2, []thriftFields{
+			{{4, "schema"}, {5, int32(1)}},
+			{{1, int32(parquetInt64)}, {3, int32(parquetRepeated)}, {4, "tags"}},
+		}},
+		{3, int64(0)},
+		{4, []thriftFields{}},
+	}.encode()
+	file := append([]byte("PAR1"), meta...)
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(meta)))
+	file = append(file, "PAR1"...)
+	if _, err := FromParquet[map[string]any](bytes.NewReader(file)); err == nil || !strings.Contains(err.Error(), "repeated") {
+		t.Errorf("Expected repeated field error, got %v", err)
+	}
+
+	type Node struct {
+		Next *Node
+	}
+	if err := From([]Node{{}}).WriteParquet(&buf); err == nil {
+		t.Error("Expected error for recursive type")
+	}
+}
+
+// TestParquetLayout checks the bytes of a small uncompressed file against
+// the layout given by the Parquet format spec, rather than against our own
+// reader.
+func TestParquetLayout(t *testing.T) {
+	type Row struct {
+		ID   int32
+		Name string `plygo:"name"`
+	}
+	var buf bytes.Buffer
+	err := From([]Row{{7, "a"}}).WriteParquet(&buf, ParquetCompression(ParquetUncompressed), ParquetDictionary(false))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data := buf.Bytes()
+
+	// "PAR1", then a compact-protocol PageHeader: type DATA_PAGE, sizes,
+	// and a DataPageHeader of 1 value, PLAIN, RLE levels; then the value
+	idPage := []byte{
+		'P', 'A', 'R', '1',
+		0x15, 0x00, 0x15, 0x08, 0x15, 0x08,
+		0x2c, 0x15, 0x02, 0x15, 0x00, 0x15, 0x06, 0x15, 0x06, 0x00, 0x00,
+		0x07, 0x00, 0x00, 0x00,
+	}
+	if !bytes.HasPrefix(data, idPage) {
+		t.Fatalf("Expected the ID page to start with\n% x\ngot\n% x", idPage, data[:len(idPage)])
+	}
+	namePage := []byte{
+		0x15, 0x00, 0x15, 0x0a, 0x15, 0x0a,
+		0x2c, 0x15, 0x02, 0x15, 0x00, 0x15, 0x06, 0x15, 0x06, 0x00, 0x00,
+		0x01, 0x00, 0x00, 0x00, 'a',
+	}
+	if !bytes.HasPrefix(data[len(idPage):], namePage) {
+		t.Fatalf("Expected the name page\n% x\ngot\n% x", namePage, data[len(idPage):len(idPage)+len(namePage)])
+	}
+
+	// the footer: FileMetaData, its little-endian length and "PAR1"
+	if !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("Expected trailing magic")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-size : len(data)-8]
+	if len(idPage)+len(namePage)+size+8 != len(data) {
+		t.Fatalf("Expected the footer right after the pages, got %d bytes in total", len(data))
+	}
+	meta, n := readThriftStruct(footer)
+	if n != size {
+		t.Fatalf("Expected a %d-byte FileMetaData, got %d", size, n)
+	}
+	if meta.int(1) != 1 || meta.int(3) != 1 || meta.str(6) != "plyGO" {
+		t.Errorf("Expected version 1, 1 row and created_by plyGO, got %v", meta)
+	}
+
+	schema := meta.list(2)
+	if len(schema) != 3 {
+		t.Fatalf("Expected 3 schema elements, got %v", schema)
+	}
+	root, id, name := schema[0].(thriftValues), schema[1].(thriftValues), schema[2].(thriftValues)
+	if root.str(4) != "schema" || root.int(5) != 2 || root.has(1) {
+		t.Errorf("Unexpected root element %v", root)
+	}
+	// INT32 is physical type 1, BYTE_ARRAY 6, REQUIRED repetition 0
+	if id.str(4) != "ID" || id.int(1) != 1 || id.int(3) != 0 {
+		t.Errorf("Unexpected ID element %v", id)
+	}
+	// UTF8 is converted type 0 and STRING logical type field 1
+	if name.str(4) != "name" || name.int(1) != 6 || name.int(3) != 0 || name.int(6) != 0 || !name.strct(10).has(1) {
+		t.Errorf("Unexpected name element %v", name)
+	}
+
+	groups := meta.list(4)
+	if len(groups) != 1 {
+		t.Fatalf("Expected one row group, got %v", groups)
+	}
+	group := groups[0].(thriftValues)
+	chunks := group.list(1)
+	if group.int(3) != 1 || len(chunks) != 2 {
+		t.Fatalf("Unexpected row group %v", group)
+	}
+	offsets := []int64{4, int64(4 + len(idPage) - 4)}
+	for i, chunk := range chunks {
+		col := chunk.(thriftValues).strct(3)
+		// codec 0 is UNCOMPRESSED; encodings 0 and 3 are PLAIN and RLE
+		if col.int(4) != 0 || col.int(5) != 1 || col.int(9) != offsets[i] || col.has(11) {
+			t.Errorf("Unexpected column chunk %d: %v", i, col)
+		}
+		if !reflect.DeepEqual(col.list(2), []any{int64(0), int64(3)}) {
+			t.Errorf("Expected PLAIN and RLE encodings, got %v", col.list(2))
+		}
+	}
+}
+
+// openParquet opens a reference file from testdata/parquet, which holds
+// files written by other Parquet implementations.
+func openParquet(t *testing.T, name string) *os.File {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", "parquet", name))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestParquetReferenceAllTypes(t *testing.T) {
+	type AllTypes struct {
+		ID        int32     `plygo:"id"`
+		Bool      bool      `plygo:"bool_col"`
+		SmallInt  int16     `plygo:"smallint_col"`
+		BigInt    int64     `plygo:"bigint_col"`
+		Float     float32   `plygo:"float_col"`
+		Double    float64   `plygo:"double_col"`
+		Date      []byte    `plygo:"date_string_col"`
+		Timestamp time.Time `plygo:"timestamp_col"`
+	}
+
+	p, err := FromParquet[AllTypes](openParquet(t, "alltypes_plain.parquet"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows := p.Collect()
+	ids := make([]int32, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	if !reflect.DeepEqual(ids, []int32{4, 5, 6, 7, 2, 3, 0, 1}) {
+		t.Fatalf("Expected ids [4 5 6 7 2 3 0 1], got %v", ids)
+	}
+	first := AllTypes{4, true, 0, 0, 0, 0, []byte("03/01/09"), time.Date(2009, 3, 1, 0, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(rows[0], first) {
+		t.Errorf("Expected %+v, got %+v", first, rows[0])
+	}
+	second := AllTypes{5, false, 1, 10, 1.1, 10.1, []byte("03/01/09"), time.Date(2009, 3, 1, 0, 1, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(rows[1], second) {
+		t.Errorf("Expected %+v, got %+v", second, rows[1])
+	}
+
+	for name, want := range map[string][]int32{
+		"alltypes_plain.snappy.parquet": {6, 7},
+		"alltypes_dictionary.parquet":   {0, 1},
+	} {
+		p, err := FromParquet[AllTypes](openParquet(t, name))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		rows := p.Collect()
+		if len(rows) != len(want) || rows[0].ID != want[0] || rows[1].ID != want[1] {
+			t.Errorf("%s: expected ids %v, got %+v", name, want, rows)
+		}
+	}
+}
+
+func TestParquetReferenceFiles(t *testing.T) {
+	read := func(name string) []map[string]any {
+		t.Helper()
+		p, err := FromParquet[map[string]any](openParquet(t, name))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		return p.Collect()
+	}
+
+	binaries := read("binary.parquet")
+	if len(binaries) != 12 || !bytes.Equal(binaries[0]["foo"].([]byte), []byte{0}) || !bytes.Equal(binaries[11]["foo"].([]byte), []byte{11}) {
+		t.Errorf("Unexpected binary rows %v", binaries)
+	}
+
+	// a dictionary page at offset zero, written by parquet-mr 1.12
+	parts := read("dict-page-offset-zero.parquet")
+	if len(parts) != 39 || parts[0]["l_partkey"] != int64(1552) || parts[38]["l_partkey"] != int64(1552) {
+		t.Errorf("Expected 39 rows of l_partkey 1552, got %v", parts)
+	}
+
+	nulls := read("nulls.snappy.parquet")
+	if len(nulls) != 8 {
+		t.Fatalf("Expected 8 rows, got %d", len(nulls))
+	}
+	for _, row := range nulls {
+		if v, ok := row["b_struct.b_c_int"]; !ok || v != nil {
+			t.Errorf("Expected a null nested field, got %v", row)
+		}
+	}
+
+	bools := read("rle_boolean_encoding.parquet")
+	if len(bools) != 68 {
+		t.Fatalf("Expected 68 rows, got %d", len(bools))
+	}
+	if bools[0]["datatype_boolean"] != true || bools[1]["datatype_boolean"] != false || bools[2]["datatype_boolean"] != nil {
+		t.Errorf("Unexpected leading booleans %v", bools[:3])
+	}
+
+	if nan := read("single_nan.parquet"); len(nan) != 1 || nan[0]["mycol"] != nil || len(nan[0]) != 1 {
+		t.Errorf("Expected a single null row, got %v", nan)
+	}
+	if empty := read("empty.parquet"); len(empty) != 0 {
+		t.Errorf("Expected no rows, got %v", empty)
+	}
+
+	for _, name := range []string{"int32_decimal.parquet", "int64_decimal.parquet", "byte_array_decimal.parquet",
+		"fixed_length_decimal.parquet", "fixed_length_decimal_legacy.parquet"} {
+		values := read(name)
+		if len(values) != 24 {
+			t.Fatalf("%s: expected 24 rows, got %d", name, len(values))
+		}
+		for i, row := range values {
+			if row["value"] != float64(i+1) {
+				t.Errorf("%s: expected %d at row %d, got %v", name, i+1, i, row["value"])
+				break
+			}
+		}
+	}
+
+	if _, err := FromParquet[map[string]any](openParquet(t, "datapage_v2.snappy.parquet")); err == nil || !strings.Contains(err.Error(), "repeated") {
+		t.Errorf("Expected repeated field error, got %v", err)
+	}
+}
+
+func TestRLEHybrid(t *testing.T) {
+	// the bit-packing example from the Parquet encoding spec
+	packed := appendRLE(nil, []int{0, 1, 2, 3, 4, 5, 6, 7}, 3)
+	if !bytes.Equal(packed, []byte{0x03, 0x88, 0xc6, 0xfa}) {
+		t.Errorf("Expected spec bit-packed bytes, got %x", packed)
+	}
+
+	values := []int{5, 5, 5, 5, 5, 5, 5, 5, 5, 1, 2, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 7}
+	encoded := appendRLE(nil, values, 3)
+	if got := decodeRLE(encoded, 3, len(values)); !reflect.DeepEqual(got, values) {
+		t.Errorf("Expected %v, got %v", values, got)
+	}
+	if got := decodeRLE([]byte{0x06, 0x01}, 1, 3); !reflect.DeepEqual(got, []int{1, 1, 1}) {
+		t.Errorf("Expected a run of ones, got %v", got)
+	}
+}
+
+func TestThriftCompact(t *testing.T) {
+	encoded := thriftFields{
+		{1, int32(-3)},
+		{2, true},
+		{3, false},
+		{20, "far"},
+		{21, []int32{1, 2}},
+		{22, thriftFields{{1, int64(1 << 40)}, {2, int8(-1)}}},
+	}.encode()
+
+	values, n := readThriftStruct(encoded)
+	if n != len(encoded) {
+		t.Errorf("Expected to consume %d bytes, got %d", len(encoded), n)
+	}
+	if values.int(1) != -3 || values[2] != true || values[3] != false || values.str(20) != "far" {
+		t.Errorf("Unexpected values %v", values)
+	}
+	if list := values.list(21); len(list) != 2 || list[1] != int64(2) {
+		t.Errorf("Unexpected list %v", list)
+	}
+	if inner := values.strct(22); inner.int(1) != 1<<40 || inner.int(2) != -1 {
+		t.Errorf("Unexpected struct %v", inner)
+	}
+}
@@ -1,6 +1,9 @@
 package plygo
 
 import (
+	"cmp"
+	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"sort"
@@ -11,6 +14,8 @@ import (
 type Pipeline[T any] struct {
 	data          []T
 	originalIndex []int
+	columns       []string
+	sources       []int
 }
 
 type PositionIndex struct {
@@ -46,21 +51,16 @@ if len(indices) == 0 {
 return &Pipeline[T]{data: []T{}, originalIndex: []int{}}
 }
 
-result := make([]T, 0, len(indices))
-resultIdx := make([]int, 0, len(indices))
+positions := make([]int, 0, len(indices))
 
 for _, idx := range indices {
 pos := p.normalizeIndex(idx)
 if pos >= 0 && pos < len(p.data) {
-result = append(result, p.data[pos])
-resultIdx = append(resultIdx, p.originalIndex[pos])
+positions = append(positions, pos)
 }
 }
 
-return &Pipeline[T]{
-data:          result,
-originalIndex: resultIdx,
-}
+return p.subset(positions)
 }
 
 func (p *Pipeline[T]) RowRange(start, end int) *Pipeline[T] {
@@ -87,16 +87,7 @@ if startPos >= endPos {
 return &Pipeline[T]{data: []T{}, originalIndex: []int{}}
 }
 
-result := make([]T, endPos-startPos)
-copy(result, p.data[startPos:endPos])
-
-resultIdx := make([]int, endPos-startPos)
-copy(resultIdx, p.originalIndex[startPos:endPos])
-
-return &Pipeline[T]{
-data:          result,
-originalIndex: resultIdx,
-}
+return p.span(startPos, endPos)
 }
 
 func (p *Pipeline[T]) normalizeIndex(idx int) int {
@@ -109,6 +100,35 @@ return len(p.data) + idx
 return -1
 }
 
+func (p *Pipeline[T]) subset(positions []int) *Pipeline[T] {
+	result := make([]T, len(positions))
+	resultIdx := make([]int, len(positions))
+	var resultSrc []int
+	if p.sources != nil {
+		resultSrc = make([]int, len(positions))
+	}
+
+	for i, pos := range positions {
+		result[i] = p.data[pos]
+		if pos < len(p.originalIndex) {
+			resultIdx[i] = p.originalIndex[pos]
+		}
+		if resultSrc != nil && pos < len(p.sources) {
+			resultSrc[i] = p.sources[pos]
+		}
+	}
+
+	return &Pipeline[T]{data: result, originalIndex: resultIdx, columns: p.columns, sources: resultSrc}
+}
+
+func (p *Pipeline[T]) span(start, end int) *Pipeline[T] {
+	positions := make([]int, 0, max(end-start, 0))
+	for i := start; i < end; i++ {
+		positions = append(positions, i)
+	}
+	return p.subset(positions)
+}
+
 func (p *Pipeline[T]) Tail(n int) *Pipeline[T] {
 if n <= 0 {
 return &Pipeline[T]{data: []T{}, originalIndex: []int{}}
@@ -117,17 +137,7 @@ if n >= len(p.data) {
 return p
 }
 
-start := len(p.data) - n
-result := make([]T, n)
-copy(result, p.data[start:])
-
-resultIdx := make([]int, n)
-copy(resultIdx, p.originalIndex[start:])
-
-return &Pipeline[T]{
-data:          result,
-originalIndex: resultIdx,
-}
+return p.span(len(p.data)-n, len(p.data))
 }
 
 func (p *Pipeline[T]) Sample(n int) *Pipeline[T] {
@@ -142,18 +152,7 @@ rand.Seed(time.Now().UnixNano())
 indices := rand.Perm(len(p.data))[:n]
 sort.Ints(indices)
 
-result := make([]T, n)
-resultIdx := make([]int, n)
-
-for i, idx := range indices {
-result[i] = p.data[idx]
-resultIdx[i] = p.originalIndex[idx]
-}
-
-return &Pipeline[T]{
-data:          result,
-originalIndex: resultIdx,
-}
+return p.subset(indices)
 }
 
 func (p *Pipeline[T]) Slice(start, end, step int) *Pipeline[T] {
@@ -181,25 +180,19 @@ if endPos > len(p.data) {
 endPos = len(p.data)
 }
 
-result := make([]T, 0)
-resultIdx := make([]int, 0)
+positions := make([]int, 0)
 
 if step > 0 {
 for i := startPos; i < endPos; i += step {
-result = append(result, p.data[i])
-resultIdx = append(resultIdx, p.originalIndex[i])
+positions = append(positions, i)
 }
 } else {
 for i := endPos - 1; i >= startPos; i += step {
-result = append(result, p.data[i])
-resultIdx = append(resultIdx, p.originalIndex[i])
+positions = append(positions, i)
 }
 }
 
-return &Pipeline[T]{
-data:          result,
-originalIndex: resultIdx,
-}
+return p.subset(positions)
 }
 
 func (p *Pipeline[T]) Positions() PositionIndex {
@@ -214,6 +207,10 @@ return p.originalIndex
 }
 
 func (p *Pipeline[T]) FieldNames() []string {
+if len(p.columns) > 0 {
+return append([]string(nil), p.columns...)
+}
+
 if len(p.data) == 0 {
 return []string{}
 }
@@ -319,18 +316,16 @@ func (p *Pipeline[T]) WhereSome(conditions ...*ConditionGroup[T]) *Pipeline[T] {
 		return p
 	}
 
-	result := make([]T, 0)
-	resultIdx := make([]int, 0)
+	positions := make([]int, 0)
 	for i, item := range p.data {
 		for _, cond := range conditions {
 			if cond.evaluate(item) {
-				result = append(result, item)
-				resultIdx = append(resultIdx, p.originalIndex[i])
+				positions = append(positions, i)
 				break
 			}
 		}
 	}
-	return &Pipeline[T]{data: result, originalIndex: resultIdx}
+	return p.subset(positions)
 }
 
 func (p *Pipeline[T]) WhereEvery(conditions ...*ConditionGroup[T]) *Pipeline[T] {
@@ -338,8 +333,7 @@ func (p *Pipeline[T]) WhereEvery(conditions ...*ConditionGroup[T]) *Pipeline[T]
 		return p
 	}
 
-	result := make([]T, 0)
-	resultIdx := make([]int, 0)
+	positions := make([]int, 0)
 	for i, item := range p.data {
 		match := true
 		for _, cond := range conditions {
@@ -349,11 +343,10 @@ func (p *Pipeline[T]) WhereEvery(conditions ...*ConditionGroup[T]) *Pipeline[T]
 			}
 		}
 		if match {
-			result = append(result, item)
-			resultIdx = append(resultIdx, p.originalIndex[i])
+			positions = append(positions, i)
 		}
 	}
-	return &Pipeline[T]{data: result, originalIndex: resultIdx}
+	return p.subset(positions)
 }
 
 func (p *Pipeline[T]) Select(fields ...string) *Selection[T] {
@@ -384,38 +377,36 @@ func (p *Pipeline[T]) Transform(fn func(T) T) *Pipeline[T] {
 	for i, item := range p.data {
 		result[i] = fn(item)
 	}
-	return &Pipeline[T]{data: result, originalIndex: resultIdx}
+	return &Pipeline[T]{data: result, originalIndex: resultIdx, columns: p.columns, sources: p.sources}
 }
 
 func (p *Pipeline[T]) Limit(n int) *Pipeline[T] {
 	if n >= len(p.data) {
 		return p
 	}
-	return &Pipeline[T]{data: p.data[:n], originalIndex: p.originalIndex[:n]}
+	return p.span(0, n)
 }
 
 func (p *Pipeline[T]) Skip(n int) *Pipeline[T] {
 	if n >= len(p.data) {
 		return &Pipeline[T]{data: []T{}, originalIndex: []int{}}
 	}
-	return &Pipeline[T]{data: p.data[n:], originalIndex: p.originalIndex[n:]}
+	return p.span(n, len(p.data))
 }
 
 func (p *Pipeline[T]) Distinct(field string) *Pipeline[T] {
 	seen := make(map[any]bool)
-	result := make([]T, 0)
-	resultIdx := make([]int, 0)
+	positions := make([]int, 0)
 
 	for i, item := range p.data {
 		val := getFieldValue(item, field)
 		key := valueKey(val)
 		if !seen[key] {
 			seen[key] = true
-			result = append(result, item)
-			resultIdx = append(resultIdx, p.originalIndex[i])
+			positions = append(positions, i)
 		}
 	}
-	return &Pipeline[T]{data: result, originalIndex: resultIdx}
+	return p.subset(positions)
 }
 
 func (p *Pipeline[T]) Collect() []T {
@@ -629,64 +620,44 @@ func (c *Condition[T]) Or(field string) *Condition[T] {
 }
 
 func (c *Condition[T]) Where(field string) *Condition[T] {
-	filtered := c.execute()
-	// Track original indices through filter chain
-	indices := make([]int, 0, len(filtered))
-	for i, item := range c.pipeline.data {
-		for _, fitem := range filtered {
-			if reflect.DeepEqual(item, fitem) {
-				indices = append(indices, c.pipeline.originalIndex[i])
-				break
-			}
-		}
-	}
 	return &Condition[T]{
-		pipeline: &Pipeline[T]{data: filtered, originalIndex: indices},
+		pipeline: c.executePipeline(),
 		field:    field,
 		filters:  make([]filter[T], 0),
 	}
 }
 
 func (c *Condition[T]) Select(fields ...string) *Selection[T] {
-	filtered := c.execute()
 	return &Selection[T]{
-		pipeline: &Pipeline[T]{data: filtered},
+		pipeline: c.executePipeline(),
 		fields:   fields,
 	}
 }
 
 func (c *Condition[T]) OrderBy(field string) *Sorter[T] {
-	filtered := c.execute()
 	return &Sorter[T]{
-		pipeline: &Pipeline[T]{data: filtered},
+		pipeline: c.executePipeline(),
 		sorts:    []sortField{{field: field, desc: false}},
 	}
 }
 
 func (c *Condition[T]) GroupBy(field string) *Grouping[T] {
-	filtered := c.execute()
 	return &Grouping[T]{
-		pipeline: &Pipeline[T]{data: filtered},
+		pipeline: c.executePipeline(),
 		field:    field,
 	}
 }
 
 func (c *Condition[T]) Transform(fn func(T) T) *Pipeline[T] {
-	filtered := c.execute()
-	p := &Pipeline[T]{data: filtered}
-	return p.Transform(fn)
+	return c.executePipeline().Transform(fn)
 }
 
 func (c *Condition[T]) Limit(n int) *Pipeline[T] {
-	filtered := c.execute()
-	p := &Pipeline[T]{data: filtered}
-	return p.Limit(n)
+	return c.executePipeline().Limit(n)
 }
 
 func (c *Condition[T]) Distinct(field string) *Pipeline[T] {
-	filtered := c.execute()
-	p := &Pipeline[T]{data: filtered}
-	return p.Distinct(field)
+	return c.executePipeline().Distinct(field)
 }
 
 func (c *Condition[T]) Collect() []T {
@@ -694,29 +665,7 @@ func (c *Condition[T]) Collect() []T {
 }
 
 func (c *Condition[T]) Positions() PositionIndex {
-	// Execute filters to get matching items
-	filtered := c.execute()
-	
-	if len(filtered) == 0 {
-		return PositionIndex{Rows: []int{}, Cols: []int{}}
-	}
-	
-	// Build a map of filtered items for matching
-	filteredMap := make(map[any]bool)
-	for _, item := range filtered {
-		filteredMap[item] = true
-	}
-	
-	// Find original indices of filtered items
-	indices := make([]int, 0, len(filtered))
-	for i, item := range c.pipeline.data {
-		if filteredMap[item] {
-			indices = append(indices, c.pipeline.originalIndex[i])
-			delete(filteredMap, item)  // Remove to handle duplicates correctly
-		}
-	}
-	
-	return PositionIndex{Rows: indices, Cols: []int{}}
+	return PositionIndex{Rows: c.executePipeline().originalIndex, Cols: []int{}}
 }
 
 func (c *Condition[T]) Which() []int {
@@ -737,6 +686,16 @@ func (c *Condition[T]) execute() []T {
 	return result
 }
 
+func (c *Condition[T]) executePipeline() *Pipeline[T] {
+	positions := make([]int, 0, len(c.pipeline.data))
+	for i, item := range c.pipeline.data {
+		if c.evaluate(item) {
+			positions = append(positions, i)
+		}
+	}
+	return c.pipeline.subset(positions)
+}
+
 func (c *Condition[T]) evaluate(item T) bool {
 	if len(c.filters) == 0 {
 		return true
@@ -875,7 +834,7 @@ type Selection[T any] struct {
 func (s *Selection[T]) Where(field string) *ConditionMap {
 	selected := s.execute()
 	return &ConditionMap{
-		pipeline: &Pipeline[map[string]any]{data: selected},
+		pipeline: &Pipeline[map[string]any]{data: selected, originalIndex: s.pipeline.originalIndex, columns: s.fields},
 		field:    field,
 		filters:  make([]filter[map[string]any], 0),
 	}
@@ -884,7 +843,7 @@ func (s *Selection[T]) Where(field string) *ConditionMap {
 func (s *Selection[T]) OrderBy(field string) *SorterMap {
 	selected := s.execute()
 	return &SorterMap{
-		pipeline: &Pipeline[map[string]any]{data: selected},
+		pipeline: &Pipeline[map[string]any]{data: selected, originalIndex: s.pipeline.originalIndex, columns: s.fields},
 		sorts:    []sortField{{field: field, desc: false}},
 	}
 }
@@ -892,7 +851,7 @@ func (s *Selection[T]) OrderBy(field string) *SorterMap {
 func (s *Selection[T]) GroupBy(field string) *GroupingMap {
 	selected := s.execute()
 	return &GroupingMap{
-		pipeline: &Pipeline[map[string]any]{data: selected},
+		pipeline: &Pipeline[map[string]any]{data: selected, originalIndex: s.pipeline.originalIndex, columns: s.fields},
 		field:    field,
 	}
 }
@@ -953,6 +912,13 @@ func (s *Selection[T]) execute() []map[string]any {
 					row[fieldName] = field.Interface()
 				}
 			}
+		} else if v.Kind() == reflect.Map {
+			for _, fieldName := range s.fields {
+				field := v.MapIndex(reflect.ValueOf(fieldName))
+				if field.IsValid() {
+					row[fieldName] = field.Interface()
+				}
+			}
 		}
 
 		result[i] = row
@@ -996,6 +962,13 @@ func (c *ConditionMap) Or(field string) *ConditionMap {
 	return c
 }
 
+func (c *ConditionMap) GroupBy(field string) *GroupingMap {
+	return &GroupingMap{
+		pipeline: c.executePipeline(),
+		field:    field,
+	}
+}
+
 func (c *ConditionMap) Collect() []map[string]any {
 	return c.execute()
 }
@@ -1007,20 +980,32 @@ func (c *ConditionMap) execute() []map[string]any {
 
 	result := make([]map[string]any, 0)
 	for _, item := range c.pipeline.data {
-		match := true
-		for _, f := range c.filters {
-			if !f.fn(item) {
-				match = false
-				break
-			}
-		}
-		if match {
+		if c.evaluate(item) {
 			result = append(result, item)
 		}
 	}
 	return result
 }
 
+func (c *ConditionMap) executePipeline() *Pipeline[map[string]any] {
+	positions := make([]int, 0, len(c.pipeline.data))
+	for i, item := range c.pipeline.data {
+		if c.evaluate(item) {
+			positions = append(positions, i)
+		}
+	}
+	return c.pipeline.subset(positions)
+}
+
+func (c *ConditionMap) evaluate(item map[string]any) bool {
+	for _, f := range c.filters {
+		if !f.fn(item) {
+			return false
+		}
+	}
+	return true
+}
+
 type sortField struct {
 	field string
 	desc  bool
@@ -1051,32 +1036,26 @@ func (s *Sorter[T]) ThenBy(field string) *Sorter[T] {
 }
 
 func (s *Sorter[T]) Where(field string) *Condition[T] {
-	sorted := s.execute()
 	return &Condition[T]{
-		pipeline: &Pipeline[T]{data: sorted},
+		pipeline: s.executePipeline(),
 		field:    field,
 		filters:  make([]filter[T], 0),
 	}
 }
 
 func (s *Sorter[T]) Select(fields ...string) *Selection[T] {
-	sorted := s.execute()
 	return &Selection[T]{
-		pipeline: &Pipeline[T]{data: sorted},
+		pipeline: s.executePipeline(),
 		fields:   fields,
 	}
 }
 
 func (s *Sorter[T]) Limit(n int) *Pipeline[T] {
-	sorted := s.execute()
-	p := &Pipeline[T]{data: sorted}
-	return p.Limit(n)
+	return s.executePipeline().Limit(n)
 }
 
 func (s *Sorter[T]) Skip(n int) *Pipeline[T] {
-	sorted := s.execute()
-	p := &Pipeline[T]{data: sorted}
-	return p.Skip(n)
+	return s.executePipeline().Skip(n)
 }
 
 func (s *Sorter[T]) Collect() []T {
@@ -1087,27 +1066,35 @@ func (s *Sorter[T]) execute() []T {
 	if len(s.sorts) == 0 {
 		return s.pipeline.data
 	}
+	return s.executePipeline().data
+}
 
-	result := make([]T, len(s.pipeline.data))
-	copy(result, s.pipeline.data)
+// executePipeline sorts row positions rather than rows, so the result keeps
+// the original indices and sources.
+func (s *Sorter[T]) executePipeline() *Pipeline[T] {
+	positions := make([]int, len(s.pipeline.data))
+	for i := range positions {
+		positions[i] = i
+	}
 
-	sort.Slice(result, func(i, j int) bool {
-		for _, sf := range s.sorts {
-			vi := getFieldValue(result[i], sf.field)
-			vj := getFieldValue(result[j], sf.field)
+	sort.SliceStable(positions, func(i, j int) bool {
+		return lessBy(s.sorts, s.pipeline.data[positions[i]], s.pipeline.data[positions[j]])
+	})
 
-			cmp := compareValues(vi, vj)
-			if cmp != 0 {
-				if sf.desc {
-					return cmp > 0
-				}
-				return cmp < 0
+	return s.pipeline.subset(positions)
+}
+
+func lessBy(sorts []sortField, a, b any) bool {
+	for _, sf := range sorts {
+		cmp := compareValues(getFieldValue(a, sf.field), getFieldValue(b, sf.field))
+		if cmp != 0 {
+			if sf.desc {
+				return cmp > 0
 			}
+			return cmp < 0
 		}
-		return false
-	})
-
-	return result
+	}
+	return false
 }
 
 type SorterMap struct {
@@ -1161,13 +1148,15 @@ func (s *SorterMap) execute() []map[string]any {
 type Grouping[T any] struct {
 	pipeline *Pipeline[T]
 	field    string
+	fields   []string
+	bucket   func(any) any
 }
 
 func (g *Grouping[T]) Count() map[any]int {
 	result := make(map[any]int)
 
 	for _, item := range g.pipeline.data {
-		key := valueKey(getFieldValue(item, g.field))
+		key := g.keyOf(item)
 		result[key]++
 	}
 
@@ -1178,7 +1167,7 @@ func (g *Grouping[T]) Sum(sumField string) map[any]float64 {
 	result := make(map[any]float64)
 
 	for _, item := range g.pipeline.data {
-		key := valueKey(getFieldValue(item, g.field))
+		key := g.keyOf(item)
 		val := toFloat64(getFieldValue(item, sumField))
 		result[key] += val
 	}
@@ -1191,7 +1180,7 @@ func (g *Grouping[T]) Avg(avgField string) map[any]float64 {
 	counts := make(map[any]int)
 
 	for _, item := range g.pipeline.data {
-		key := valueKey(getFieldValue(item, g.field))
+		key := g.keyOf(item)
 		val := toFloat64(getFieldValue(item, avgField))
 		sums[key] += val
 		counts[key]++
@@ -1209,7 +1198,7 @@ func (g *Grouping[T]) Min(minField string) map[any]any {
 	result := make(map[any]any)
 
 	for _, item := range g.pipeline.data {
-		key := valueKey(getFieldValue(item, g.field))
+		key := g.keyOf(item)
 		val := getFieldValue(item, minField)
 
 		if existing, ok := result[key]; !ok || compareValues(val, existing) < 0 {
@@ -1224,7 +1213,7 @@ func (g *Grouping[T]) Max(maxField string) map[any]any {
 	result := make(map[any]any)
 
 	for _, item := range g.pipeline.data {
-		key := valueKey(getFieldValue(item, g.field))
+		key := g.keyOf(item)
 		val := getFieldValue(item, maxField)
 
 		if existing, ok := result[key]; !ok || compareValues(val, existing) > 0 {
@@ -1235,33 +1224,9 @@ func (g *Grouping[T]) Max(maxField string) map[any]any {
 	return result
 }
 
-type GroupingMap struct {
-	pipeline *Pipeline[map[string]any]
-	field    string
-}
-
-func (g *GroupingMap) Count() map[any]int {
-	result := make(map[any]int)
-
-	for _, item := range g.pipeline.data {
-		key := valueKey(item[g.field])
-		result[key]++
-	}
-
-	return result
-}
-
-func (g *GroupingMap) Sum(sumField string) map[any]float64 {
-	result := make(map[any]float64)
-
-	for _, item := range g.pipeline.data {
-		key := valueKey(item[g.field])
-		val := toFloat64(item[sumField])
-		result[key] += val
-	}
-
-	return result
-}
+// GroupingMap is the grouping produced from a Selection. It shares the
+// implementation of Grouping, so every aggregate works on selected rows too.
+type GroupingMap = Grouping[map[string]any]
 
 func getFieldValue(item any, fieldName string) any {
 	v := reflect.ValueOf(item)
@@ -1325,14 +1290,15 @@ func compareValues(a, b any) int {
 		return 1
 	}
 
-	aVal := toFloat64(a)
-	bVal := toFloat64(b)
-
-	if aVal < bVal {
-		return -1
+	if ta, ok := toTime(a); ok {
+		if tb, ok := toTime(b); ok {
+			return ta.Compare(tb)
+		}
 	}
-	if aVal > bVal {
-		return 1
+
+	// cmp.Compare orders NaN before every number, so sorts stay consistent
+	if c := cmp.Compare(toFloat64(a), toFloat64(b)); c != 0 {
+		return c
 	}
 
 	if sa, ok := a.(string); ok {
@@ -1379,13 +1345,22 @@ func toFloat64(v any) float64 {
 	return 0
 }
 
+type complexKey string
+
 func valueKey(v any) any {
 	if v == nil {
 		return "<nil>"
 	}
+	// NaN never equals itself, so it would never find its own map entry
+	if f, ok := v.(float64); ok && math.IsNaN(f) {
+		return complexKey("NaN")
+	}
+	if f, ok := v.(float32); ok && math.IsNaN(float64(f)) {
+		return complexKey("NaN")
+	}
 	rv := reflect.ValueOf(v)
-	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map {
-		return "<complex>"
+	if !rv.Comparable() {
+		return complexKey(fmt.Sprintf("%T:%#v", v, v))
 	}
 	return v
 }
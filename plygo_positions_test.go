@@ -459,3 +459,38 @@ if !pi.IsMatrix() {
 t.Error("Expected IsMatrix to be true")
 }
 }
+
+func TestPositionTracking_ThroughChains(t *testing.T) {
+people := []TestPerson{
+{"Alice", 30, "NYC", 75000},
+{"Bob", 25, "LA", 60000},
+{"Charlie", 35, "NYC", 90000},
+{"Diana", 28, "LA", 70000},
+}
+
+groups := From(people).Where("Age").GreaterThan(26).Select("Name", "City").GroupBy("City").Groups()
+if len(groups) != 2 || !reflect.DeepEqual(groups[0].Rows.Which(), []int{1, 3}) || !reflect.DeepEqual(groups[1].Rows.Which(), []int{4}) {
+t.Errorf("Expected groups at [1 3] and [4], got %v", groups)
+}
+
+groups = From(people).Select("Name", "Age").Where("Age").GreaterThan(26).GroupBy("Age").Groups()
+if len(groups) != 3 || !reflect.DeepEqual(groups[1].Rows.Which(), []int{3}) {
+t.Errorf("Expected Charlie's group at [3], got %v", groups)
+}
+
+if got := From(people).OrderBy("Salary").Desc().Where("Age").GreaterThan(26).Which(); !reflect.DeepEqual(got, []int{3, 1, 4}) {
+t.Errorf("Expected [3 1 4], got %v", got)
+}
+if got := From(people).OrderBy("Age").Limit(2).Which(); !reflect.DeepEqual(got, []int{2, 4}) {
+t.Errorf("Expected [2 4], got %v", got)
+}
+if got := From(people).OrderBy("Age").Skip(3).Which(); !reflect.DeepEqual(got, []int{3}) {
+t.Errorf("Expected [3], got %v", got)
+}
+if got := From(people).OrderBy("Name").Desc().Select("Name").Positions().Rows; !reflect.DeepEqual(got, []int{4, 3, 2, 1}) {
+t.Errorf("Expected [4 3 2 1], got %v", got)
+}
+if got := From(people).Where("City").Equals("LA").Limit(1).Which(); !reflect.DeepEqual(got, []int{2}) {
+t.Errorf("Expected [2], got %v", got)
+}
+}
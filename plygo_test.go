@@ -12,6 +12,20 @@ type Person struct {
 	Active bool
 }
 
+// samplePeople is the shared fixture for tests that need more than a
+// handful of rows: three cities, repeated ages and a mix of active flags.
+func samplePeople() []Person {
+	return []Person{
+		{"Alice", 30, "NYC", 75000, true},
+		{"Bob", 25, "LA", 60000, true},
+		{"Charlie", 35, "NYC", 90000, false},
+		{"Diana", 28, "Chicago", 70000, true},
+		{"Eve", 32, "LA", 85000, true},
+		{"Frank", 41, "NYC", 95000, false},
+		{"Grace", 25, "Chicago", 62000, true},
+	}
+}
+
 func TestBasicFiltering(t *testing.T) {
 	people := []Person{
 		{"Alice", 30, "NYC", 75000, true},
@@ -0,0 +1,169 @@
+package plygo
+
+import "fmt"
+
+// AggFunc reduces the values that fall into one cell of a Pivot.
+type AggFunc func(values []any) any
+
+func AggSum(values []any) any {
+	total := 0.0
+	for _, v := range values {
+		total += toFloat64(v)
+	}
+	return total
+}
+
+func AggAvg(values []any) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return AggSum(values).(float64) / float64(len(values))
+}
+
+func AggCount(values []any) any {
+	return len(values)
+}
+
+func AggMin(values []any) any {
+	var result any
+	for i, v := range values {
+		if i == 0 || compareValues(v, result) < 0 {
+			result = v
+		}
+	}
+	return result
+}
+
+func AggMax(values []any) any {
+	var result any
+	for i, v := range values {
+		if i == 0 || compareValues(v, result) > 0 {
+			result = v
+		}
+	}
+	return result
+}
+
+func AggFirst(values []any) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+func AggLast(values []any) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[len(values)-1]
+}
+
+// Pivot reshapes long data to wide: one row per distinct index value and
+// one column per distinct value of columns, each cell holding agg applied
+// to the matching values. Missing cells are nil. Rows and columns appear in
+// order of first appearance, and each row keeps the original index of the
+// first source row for its index value. A column value that would reuse the
+// index name (or an earlier column) gets a " (2)", " (3)"... suffix.
+func (p *Pipeline[T]) Pivot(index, columns, values string, agg AggFunc) *Pipeline[map[string]any] {
+	rowKeys := make([]any, 0)
+	rowValues := make(map[any]any)
+	rowIdx := make(map[any]int)
+	colNames := make([]string, 0)
+	colName := make(map[string]string)
+	taken := map[string]bool{index: true}
+	cells := make(map[any]map[string][]any)
+
+	for i, item := range p.data {
+		rowVal := getFieldValue(item, index)
+		rowKey := valueKey(rowVal)
+		if _, ok := cells[rowKey]; !ok {
+			rowKeys = append(rowKeys, rowKey)
+			rowValues[rowKey] = rowVal
+			if i < len(p.originalIndex) {
+				rowIdx[rowKey] = p.originalIndex[i]
+			}
+			cells[rowKey] = make(map[string][]any)
+		}
+
+		raw := fmt.Sprint(getFieldValue(item, columns))
+		col, ok := colName[raw]
+		if !ok {
+			col = raw
+			for n := 2; taken[col]; n++ {
+				col = fmt.Sprintf("%s (%d)", raw, n)
+			}
+			colName[raw] = col
+			taken[col] = true
+			colNames = append(colNames, col)
+		}
+
+		cells[rowKey][col] = append(cells[rowKey][col], getFieldValue(item, values))
+	}
+
+	result := make([]map[string]any, len(rowKeys))
+	resultIdx := make([]int, len(rowKeys))
+	for i, rowKey := range rowKeys {
+		row := make(map[string]any, len(colNames)+1)
+		row[index] = rowValues[rowKey]
+		for _, col := range colNames {
+			if vals, ok := cells[rowKey][col]; ok {
+				row[col] = agg(vals)
+			} else {
+				row[col] = nil
+			}
+		}
+		result[i] = row
+		resultIdx[i] = rowIdx[rowKey]
+	}
+
+	return &Pipeline[map[string]any]{
+		data:          result,
+		originalIndex: resultIdx,
+		columns:       append([]string{index}, colNames...),
+	}
+}
+
+// Melt reshapes wide data to long: each source row becomes one row per
+// value field, holding the id fields plus "variable" (the field name) and
+// "value". With no valueFields, every field that is not an id is melted.
+// Rows keep the original index of their source row.
+func (p *Pipeline[T]) Melt(idFields, valueFields []string) *Pipeline[map[string]any] {
+	if len(valueFields) == 0 {
+		ids := make(map[string]bool, len(idFields))
+		for _, f := range idFields {
+			ids[f] = true
+		}
+		for _, f := range p.FieldNames() {
+			if !ids[f] {
+				valueFields = append(valueFields, f)
+			}
+		}
+	}
+
+	result := make([]map[string]any, 0, len(p.data)*len(valueFields))
+	resultIdx := make([]int, 0, len(p.data)*len(valueFields))
+
+	for i, item := range p.data {
+		for _, field := range valueFields {
+			row := make(map[string]any, len(idFields)+2)
+			for _, id := range idFields {
+				row[id] = getFieldValue(item, id)
+			}
+			row["variable"] = field
+			row["value"] = getFieldValue(item, field)
+			result = append(result, row)
+
+			idx := 0
+			if i < len(p.originalIndex) {
+				idx = p.originalIndex[i]
+			}
+			resultIdx = append(resultIdx, idx)
+		}
+	}
+
+	return &Pipeline[map[string]any]{
+		data:          result,
+		originalIndex: resultIdx,
+		columns:       append(append([]string(nil), idFields...), "variable", "value"),
+	}
+}
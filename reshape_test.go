@@ -0,0 +1,125 @@
+package plygo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPivot(t *testing.T) {
+	wide := From(samplePeople()).Pivot("City", "Active", "Salary", AggAvg)
+
+	if !reflect.DeepEqual(wide.FieldNames(), []string{"City", "true", "false"}) {
+		t.Errorf("Unexpected columns: %v", wide.FieldNames())
+	}
+
+	expected := []map[string]any{
+		{"City": "NYC", "true": 75000.0, "false": 92500.0},
+		{"City": "LA", "true": 72500.0, "false": nil},
+		{"City": "Chicago", "true": 66000.0, "false": nil},
+	}
+	if !reflect.DeepEqual(wide.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, wide.Collect())
+	}
+	if !reflect.DeepEqual(wide.Which(), []int{1, 2, 4}) {
+		t.Errorf("Expected [1 2 4], got %v", wide.Which())
+	}
+}
+
+func TestPivot_Aggregates(t *testing.T) {
+	people := From(samplePeople())
+
+	counts := people.Pivot("City", "Active", "Salary", AggCount).Collect()
+	if counts[0]["false"] != 2 {
+		t.Errorf("Expected 2 inactive people in NYC, got %v", counts[0]["false"])
+	}
+
+	firsts := people.Pivot("City", "Active", "Salary", AggFirst).Collect()
+	if firsts[0]["false"] != 90000.0 {
+		t.Errorf("Expected first salary 90000, got %v", firsts[0]["false"])
+	}
+
+	highest := people.Pivot("Active", "City", "Salary", AggMax).Collect()
+	if highest[0]["NYC"] != 75000.0 || highest[0]["LA"] != 85000.0 || highest[1]["NYC"] != 95000.0 {
+		t.Errorf("Unexpected max pivot: %v", highest)
+	}
+}
+
+func TestPivot_MixedNumericAndEmpty(t *testing.T) {
+	rows := From([]map[string]any{
+		{"k": "a", "c": "x", "v": 1},
+		{"k": "a", "c": "x", "v": int64(2)},
+		{"k": "a", "c": "x", "v": 0.5},
+	})
+	if sum := rows.Pivot("k", "c", "v", AggSum).Collect(); sum[0]["x"] != 3.5 {
+		t.Errorf("Expected mixed numbers to sum to 3.5, got %v", sum)
+	}
+	if highest := rows.Pivot("k", "c", "v", AggMax).Collect(); highest[0]["x"] != int64(2) {
+		t.Errorf("Expected int64 2 as the maximum, got %v", highest)
+	}
+
+	empty := From([]Person{}).Pivot("City", "Active", "Salary", AggSum)
+	if empty.Count() != 0 || !reflect.DeepEqual(empty.FieldNames(), []string{"City"}) {
+		t.Errorf("Expected no rows and only the index column, got %v %v", empty.Collect(), empty.FieldNames())
+	}
+	if long := From([]Person{}).Melt([]string{"Name"}, nil); long.Count() != 0 {
+		t.Errorf("Expected no rows, got %v", long.Collect())
+	}
+}
+
+func TestPivot_ColumnNameCollision(t *testing.T) {
+	rows := From([]map[string]any{
+		{"k": "a", "c": "k", "v": 1},
+		{"k": "a", "c": "k (2)", "v": 2},
+		{"k": "b", "c": "x", "v": 3},
+	})
+	wide := rows.Pivot("k", "c", "v", AggSum)
+
+	if !reflect.DeepEqual(wide.FieldNames(), []string{"k", "k (2)", "k (2) (2)", "x"}) {
+		t.Errorf("Expected suffixed columns, got %v", wide.FieldNames())
+	}
+	expected := []map[string]any{
+		{"k": "a", "k (2)": 1.0, "k (2) (2)": 2.0, "x": nil},
+		{"k": "b", "k (2)": nil, "k (2) (2)": nil, "x": 3.0},
+	}
+	if !reflect.DeepEqual(wide.Collect(), expected) {
+		t.Errorf("Expected the index values to survive, got %v", wide.Collect())
+	}
+}
+
+func TestMelt(t *testing.T) {
+	long := From(samplePeople()[:2]).Melt([]string{"Name"}, []string{"Age", "City"})
+
+	expected := []map[string]any{
+		{"Name": "Alice", "variable": "Age", "value": 30},
+		{"Name": "Alice", "variable": "City", "value": "NYC"},
+		{"Name": "Bob", "variable": "Age", "value": 25},
+		{"Name": "Bob", "variable": "City", "value": "LA"},
+	}
+	if !reflect.DeepEqual(long.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, long.Collect())
+	}
+	if !reflect.DeepEqual(long.Which(), []int{1, 1, 2, 2}) {
+		t.Errorf("Expected [1 1 2 2], got %v", long.Which())
+	}
+	if !reflect.DeepEqual(long.FieldNames(), []string{"Name", "variable", "value"}) {
+		t.Errorf("Unexpected columns: %v", long.FieldNames())
+	}
+}
+
+func TestMelt_DefaultValueFields(t *testing.T) {
+	long := From(samplePeople()[:1]).Melt([]string{"Name"}, nil)
+	if long.Count() != 4 {
+		t.Errorf("Expected Age, City, Salary and Active to be melted, got %d rows", long.Count())
+	}
+}
+
+func TestMeltPivot_RoundTrip(t *testing.T) {
+	wide := From(samplePeople()[:2]).
+		Melt([]string{"Name"}, nil).
+		Pivot("Name", "variable", "value", AggFirst).
+		Collect()
+
+	if wide[1]["City"] != "LA" || wide[1]["Salary"] != 60000.0 {
+		t.Errorf("Expected round trip to restore values, got %v", wide)
+	}
+}
@@ -0,0 +1,227 @@
+package plygo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TotalKey is the value placed in a grouping column on subtotal and
+// grand-total rows produced by RollupBy and CubeBy.
+const TotalKey = "(total)"
+
+// Rollup computes grouped aggregates together with subtotal rows. Results
+// are map pipelines with one column per grouping field, the aggregate
+// column and an IsTotal flag, ready to be passed to Show. The aggregate
+// column is named after the aggregated field ("Count" for Count), or
+// "Sum(Amount)" and so on when that name is already a grouping field. Like
+// SQL, an empty pipeline still yields the grand-total row.
+type Rollup[T any] struct {
+	pipeline *Pipeline[T]
+	fields   []string
+	sets     [][]bool
+}
+
+// RollupBy groups hierarchically: every prefix of fields gets a subtotal
+// row, followed by a grand total.
+func (p *Pipeline[T]) RollupBy(fields ...string) *Rollup[T] {
+	sets := make([][]bool, 0, len(fields)+1)
+	for n := len(fields); n >= 0; n-- {
+		set := make([]bool, len(fields))
+		for i := 0; i < n; i++ {
+			set[i] = true
+		}
+		sets = append(sets, set)
+	}
+	return &Rollup[T]{pipeline: p, fields: fields, sets: sets}
+}
+
+// CubeBy produces subtotals for every combination of fields.
+func (p *Pipeline[T]) CubeBy(fields ...string) *Rollup[T] {
+	sets := make([][]bool, 0, 1<<len(fields))
+	for mask := 0; mask < 1<<len(fields); mask++ {
+		set := make([]bool, len(fields))
+		for i := range fields {
+			set[i] = mask&(1<<(len(fields)-1-i)) == 0
+		}
+		sets = append(sets, set)
+	}
+	return &Rollup[T]{pipeline: p, fields: fields, sets: sets}
+}
+
+func (r *Rollup[T]) Count() *Pipeline[map[string]any] {
+	return r.aggregate("Count", "", func(rows []T) any {
+		return len(rows)
+	})
+}
+
+func (r *Rollup[T]) Sum(field string) *Pipeline[map[string]any] {
+	return r.aggregate("Sum", field, func(rows []T) any {
+		total := 0.0
+		for _, item := range rows {
+			total += toFloat64(getFieldValue(item, field))
+		}
+		return total
+	})
+}
+
+func (r *Rollup[T]) Avg(field string) *Pipeline[map[string]any] {
+	return r.aggregate("Avg", field, func(rows []T) any {
+		total := 0.0
+		for _, item := range rows {
+			total += toFloat64(getFieldValue(item, field))
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return total / float64(len(rows))
+	})
+}
+
+func (r *Rollup[T]) Min(field string) *Pipeline[map[string]any] {
+	return r.aggregate("Min", field, func(rows []T) any {
+		var result any
+		for i, item := range rows {
+			val := getFieldValue(item, field)
+			if i == 0 || compareValues(val, result) < 0 {
+				result = val
+			}
+		}
+		return result
+	})
+}
+
+func (r *Rollup[T]) Max(field string) *Pipeline[map[string]any] {
+	return r.aggregate("Max", field, func(rows []T) any {
+		var result any
+		for i, item := range rows {
+			val := getFieldValue(item, field)
+			if i == 0 || compareValues(val, result) > 0 {
+				result = val
+			}
+		}
+		return result
+	})
+}
+
+type rollupRow struct {
+	ranks  []int
+	values []any
+	rows   []int
+	total  bool
+}
+
+// aggregateColumn names the result column of op over field, avoiding the
+// grouping columns and IsTotal.
+func (r *Rollup[T]) aggregateColumn(op, field string) string {
+	taken := map[string]bool{"IsTotal": true}
+	for _, f := range r.fields {
+		taken[f] = true
+	}
+
+	column := field
+	if field == "" {
+		column = op
+	}
+	if taken[column] {
+		if field == "" {
+			field = "*"
+		}
+		column = fmt.Sprintf("%s(%s)", op, field)
+	}
+	for base, n := column, 2; taken[column]; n++ {
+		column = fmt.Sprintf("%s_%d", base, n)
+	}
+	return column
+}
+
+func (r *Rollup[T]) aggregate(op, field string, fn func([]T) any) *Pipeline[map[string]any] {
+	column := r.aggregateColumn(op, field)
+	values := make([][]any, len(r.pipeline.data))
+	ranks := make([]map[any]int, len(r.fields))
+	for j := range r.fields {
+		ranks[j] = make(map[any]int)
+	}
+
+	for i, item := range r.pipeline.data {
+		values[i] = make([]any, len(r.fields))
+		for j, field := range r.fields {
+			key := valueKey(getFieldValue(item, field))
+			if _, ok := ranks[j][key]; !ok {
+				ranks[j][key] = len(ranks[j])
+			}
+			values[i][j] = getFieldValue(item, field)
+		}
+	}
+
+	groups := make([]*rollupRow, 0)
+	for _, set := range r.sets {
+		index := make(map[string]*rollupRow)
+		for i := range r.pipeline.data {
+			row := &rollupRow{
+				ranks:  make([]int, len(r.fields)),
+				values: make([]any, len(r.fields)),
+			}
+			parts := make([]string, len(r.fields))
+			for j := range r.fields {
+				if set[j] {
+					row.ranks[j] = ranks[j][valueKey(values[i][j])]
+					row.values[j] = values[i][j]
+				} else {
+					row.ranks[j] = len(ranks[j])
+					row.values[j] = TotalKey
+					row.total = true
+				}
+				parts[j] = fmt.Sprint(row.ranks[j])
+			}
+
+			key := strings.Join(parts, ",")
+			existing, ok := index[key]
+			if !ok {
+				existing = row
+				index[key] = row
+				groups = append(groups, row)
+			}
+			existing.rows = append(existing.rows, i)
+		}
+	}
+
+	if len(r.pipeline.data) == 0 {
+		grand := &rollupRow{values: make([]any, len(r.fields)), ranks: make([]int, len(r.fields))}
+		for j := range r.fields {
+			grand.values[j] = TotalKey
+			grand.total = true
+		}
+		groups = append(groups, grand)
+	}
+
+	sort.SliceStable(groups, func(a, b int) bool {
+		for j := range r.fields {
+			if groups[a].ranks[j] != groups[b].ranks[j] {
+				return groups[a].ranks[j] < groups[b].ranks[j]
+			}
+		}
+		return false
+	})
+
+	result := make([]map[string]any, len(groups))
+	for i, group := range groups {
+		members := make([]T, len(group.rows))
+		for k, pos := range group.rows {
+			members[k] = r.pipeline.data[pos]
+		}
+
+		row := make(map[string]any, len(r.fields)+2)
+		for j, field := range r.fields {
+			row[field] = group.values[j]
+		}
+		row[column] = fn(members)
+		row["IsTotal"] = group.total
+		result[i] = row
+	}
+
+	columns := append(append([]string(nil), r.fields...), column, "IsTotal")
+	out := From(result)
+	out.columns = columns
+	return out
+}
@@ -0,0 +1,128 @@
+package plygo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRollupBy(t *testing.T) {
+	result := From(samplePeople()).RollupBy("City", "Active").Sum("Salary")
+	rows := result.Collect()
+
+	expected := [][]any{
+		{"NYC", true, 75000.0, false},
+		{"NYC", false, 185000.0, false},
+		{"NYC", TotalKey, 260000.0, true},
+		{"LA", true, 145000.0, false},
+		{"LA", TotalKey, 145000.0, true},
+		{"Chicago", true, 132000.0, false},
+		{"Chicago", TotalKey, 132000.0, true},
+		{TotalKey, TotalKey, 537000.0, true},
+	}
+
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d", len(expected), len(rows))
+	}
+	for i, exp := range expected {
+		got := []any{rows[i]["City"], rows[i]["Active"], rows[i]["Salary"], rows[i]["IsTotal"]}
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("Row %d: expected %v, got %v", i, exp, got)
+		}
+	}
+
+	if !reflect.DeepEqual(result.FieldNames(), []string{"City", "Active", "Salary", "IsTotal"}) {
+		t.Errorf("Unexpected columns: %v", result.FieldNames())
+	}
+}
+
+func TestCubeBy(t *testing.T) {
+	rows := From(samplePeople()).CubeBy("City", "Active").Count().Collect()
+
+	if len(rows) != 10 {
+		t.Fatalf("Expected 10 rows (4 detail, 5 subtotals, 1 total), got %d", len(rows))
+	}
+
+	last := rows[len(rows)-1]
+	if last["City"] != TotalKey || last["Active"] != TotalKey || last["Count"] != 7 {
+		t.Errorf("Expected grand total of 7 last, got %v", last)
+	}
+
+	found := false
+	for _, row := range rows {
+		if row["City"] == TotalKey && row["Active"] == false {
+			found = true
+			if row["Count"] != 2 {
+				t.Errorf("Expected 2 inactive people, got %v", row["Count"])
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a per-flag subtotal across cities")
+	}
+}
+
+func TestRollupBy_WhereIsTotal(t *testing.T) {
+	totals := From(samplePeople()).
+		RollupBy("City").
+		Max("Salary").
+		Where("IsTotal").IsTrue().
+		Collect()
+
+	if len(totals) != 1 || totals[0]["Salary"] != 95000.0 {
+		t.Errorf("Expected a single grand total of 95000, got %v", totals)
+	}
+}
+
+func TestRollupBy_Empty(t *testing.T) {
+	rows := From([]Person{}).RollupBy("City", "Active").Count().Collect()
+	expected := []map[string]any{{"City": TotalKey, "Active": TotalKey, "Count": 0, "IsTotal": true}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected only a grand total of 0, got %v", rows)
+	}
+
+	rows = From([]Person{}).CubeBy("City").Avg("Salary").Collect()
+	if len(rows) != 1 || rows[0]["City"] != TotalKey || rows[0]["Salary"] != nil {
+		t.Errorf("Expected a nil average on the grand total, got %v", rows)
+	}
+}
+
+func TestRollupBy_ColumnCollision(t *testing.T) {
+	result := From(samplePeople()).RollupBy("City", "Salary").Sum("Salary")
+	if !reflect.DeepEqual(result.FieldNames(), []string{"City", "Salary", "Sum(Salary)", "IsTotal"}) {
+		t.Fatalf("Unexpected columns: %v", result.FieldNames())
+	}
+	last := result.Collect()[len(result.Collect())-1]
+	if last["Salary"] != TotalKey || last["Sum(Salary)"] != 537000.0 {
+		t.Errorf("Expected grouping column to survive, got %v", last)
+	}
+
+	counts := From([]map[string]any{{"Count": 1}, {"Count": 2}}).RollupBy("Count").Count()
+	if !reflect.DeepEqual(counts.FieldNames(), []string{"Count", "Count(*)", "IsTotal"}) {
+		t.Errorf("Unexpected columns: %v", counts.FieldNames())
+	}
+
+	taken := From(samplePeople()).RollupBy("Salary", "Sum(Salary)").Sum("Salary")
+	if !reflect.DeepEqual(taken.FieldNames(), []string{"Salary", "Sum(Salary)", "Sum(Salary)_2", "IsTotal"}) {
+		t.Errorf("Unexpected columns: %v", taken.FieldNames())
+	}
+}
+
+func TestRollupBy_Show(t *testing.T) {
+	output := captureOutput(func() {
+		From(samplePeople()).RollupBy("City", "Active").Avg("Salary").Show()
+	})
+
+	header := strings.Split(output, "\n")[1]
+	if strings.Index(header, "City") > strings.Index(header, "Active") ||
+		strings.Index(header, "Active") > strings.Index(header, "Salary") ||
+		strings.Index(header, "Salary") > strings.Index(header, "IsTotal") {
+		t.Errorf("Expected columns in rollup order, got %q", header)
+	}
+	if !strings.Contains(output, TotalKey) {
+		t.Error("Output should contain total rows")
+	}
+	if !strings.Contains(output, "8 rows") {
+		t.Error("Output should show 8 rows")
+	}
+}
@@ -0,0 +1,187 @@
+package plygo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Schema describes the columns of a pipeline. For map pipelines it is
+// inferred from every row, so Warnings can point out columns whose values
+// do not share a type.
+type Schema struct {
+	Columns  []Column
+	Warnings []string
+}
+
+// Column is one column of a Schema. Name is the field name accepted by
+// Select, Where and GroupBy; Header is the name WriteCSV and the other
+// writers use for it, which differs when the field has a plygo tag. Type is
+// the common type of the column's values: numbers of different types are
+// reported as float64, and any other mix (or a column with only nils) as
+// interface{}. Nulls counts rows where the value is nil or the key is
+// missing.
+type Column struct {
+	Name     string
+	Header   string
+	Type     reflect.Type
+	Nullable bool
+	Nulls    int
+}
+
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+
+// FromRecords builds a map pipeline whose columns are the union of the keys
+// of all records, unlike From which only looks at the first row. Use Schema
+// to inspect the inferred types.
+func FromRecords(records []map[string]any) *Pipeline[map[string]any] {
+	p := From(records)
+	p.columns = mapKeys(records)
+	return p
+}
+
+// Schema returns the columns of the pipeline in Show order. Struct
+// pipelines report their exported fields and field types, leaving out
+// fields tagged "-" since no writer exports them; pointer, interface, map
+// and slice fields are nullable. Map pipelines are inferred from the
+// values.
+func (p *Pipeline[T]) Schema() Schema {
+	var zero T
+	typ := reflect.TypeOf(zero)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ != nil && typ.Kind() == reflect.Struct {
+		return structSchema(typ, p.data)
+	}
+
+	fields := p.FieldNames()
+	if rows, ok := any(p.data).([]map[string]any); ok && len(p.columns) == 0 {
+		fields = mapKeys(rows)
+	}
+	return inferSchema(fields, p.data)
+}
+
+// Column returns the column with the given name, or failing that the
+// column with that header.
+func (s Schema) Column(name string) (Column, bool) {
+	for _, col := range s.Columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	for _, col := range s.Columns {
+		if col.Header == name {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+func (s Schema) String() string {
+	width := 0
+	for _, col := range s.Columns {
+		width = max(width, len(col.Name))
+	}
+
+	var b strings.Builder
+	for _, col := range s.Columns {
+		fmt.Fprintf(&b, "%-*s  %s", width, col.Name, col.Type)
+		if col.Nullable {
+			b.WriteString("  nullable")
+		}
+		b.WriteString("\n")
+	}
+	for _, w := range s.Warnings {
+		fmt.Fprintf(&b, "warning: %s\n", w)
+	}
+	return b.String()
+}
+
+func structSchema[T any](typ reflect.Type, data []T) Schema {
+	schema := Schema{Columns: make([]Column, 0, typ.NumField())}
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		key := fieldKey(sf)
+		if !sf.IsExported() || key == "" {
+			continue
+		}
+
+		col := Column{Name: sf.Name, Header: key, Type: sf.Type}
+		switch sf.Type.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			col.Nullable = true
+			for _, item := range data {
+				if isNil(getFieldValue(item, sf.Name)) {
+					col.Nulls++
+				}
+			}
+		}
+		schema.Columns = append(schema.Columns, col)
+	}
+	return schema
+}
+
+func inferSchema[T any](fields []string, data []T) Schema {
+	schema := Schema{Columns: make([]Column, 0, len(fields))}
+	for _, field := range fields {
+		col := Column{Name: field, Header: field}
+		types := make([]reflect.Type, 0, 1)
+		counts := make(map[reflect.Type]int)
+
+		for _, item := range data {
+			val := getFieldValue(item, field)
+			if isNil(val) {
+				col.Nulls++
+				continue
+			}
+			t := reflect.TypeOf(val)
+			if counts[t] == 0 {
+				types = append(types, t)
+			}
+			counts[t]++
+		}
+
+		col.Nullable = col.Nulls > 0
+		col.Type = commonType(types)
+		if col.Type == anyType && len(types) > 1 {
+			parts := make([]string, len(types))
+			for i, t := range types {
+				parts[i] = fmt.Sprintf("%s (%d)", t, counts[t])
+			}
+			schema.Warnings = append(schema.Warnings,
+				fmt.Sprintf("column %q has mixed types: %s", field, strings.Join(parts, ", ")))
+		}
+		schema.Columns = append(schema.Columns, col)
+	}
+	return schema
+}
+
+func commonType(types []reflect.Type) reflect.Type {
+	switch len(types) {
+	case 0:
+		return anyType
+	case 1:
+		return types[0]
+	}
+
+	for _, t := range types {
+		if !isNumberKind(t.Kind()) {
+			return anyType
+		}
+	}
+	return reflect.TypeOf(float64(0))
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package plygo
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type SchemaAccount struct {
+	Name    string
+	Balance float64
+	Owner   *string
+	Tags    []string
+	secret  string
+}
+
+func TestFromRecords(t *testing.T) {
+	records := []map[string]any{
+		{"name": "Alice", "age": 30},
+		{"name": "Bob", "city": "LA"},
+	}
+
+	p := FromRecords(records)
+	if !reflect.DeepEqual(p.FieldNames(), []string{"age", "city", "name"}) {
+		t.Errorf("Expected union of keys, got %v", p.FieldNames())
+	}
+	if got := len(p.Where("city").Equals("LA").Collect()); got != 1 {
+		t.Errorf("Expected 1 row in LA, got %d", got)
+	}
+}
+
+func TestSchemaInference(t *testing.T) {
+	records := []map[string]any{
+		{"name": "Alice", "age": 30, "score": 1.5, "id": "a1"},
+		{"name": "Bob", "age": nil, "score": 2, "id": 7},
+		{"name": "Carol", "score": 3.0, "id": "c3"},
+	}
+
+	schema := FromRecords(records).Schema()
+
+	expected := []Column{
+		{Name: "age", Header: "age", Type: reflect.TypeOf(0), Nullable: true, Nulls: 2},
+		{Name: "id", Header: "id", Type: anyType},
+		{Name: "name", Header: "name", Type: reflect.TypeOf("")},
+		{Name: "score", Header: "score", Type: reflect.TypeOf(0.0)},
+	}
+	if !reflect.DeepEqual(schema.Columns, expected) {
+		t.Errorf("Expected %v, got %v", expected, schema.Columns)
+	}
+
+	if len(schema.Warnings) != 1 || !strings.Contains(schema.Warnings[0], `"id" has mixed types: string (2), int (1)`) {
+		t.Errorf("Unexpected warnings %v", schema.Warnings)
+	}
+
+	col, ok := schema.Column("age")
+	if !ok || !col.Nullable {
+		t.Errorf("Expected nullable age column, got %v", col)
+	}
+	if _, ok := schema.Column("missing"); ok {
+		t.Error("Expected missing column lookup to fail")
+	}
+
+	text := schema.String()
+	if !strings.Contains(text, "age    int  nullable\n") || !strings.Contains(text, "warning: column \"id\"") {
+		t.Errorf("Unexpected schema text:\n%s", text)
+	}
+}
+
+func TestSchemaEmptyAndNaN(t *testing.T) {
+	schema := FromRecords(nil).Schema()
+	if len(schema.Columns) != 0 || len(schema.Warnings) != 0 || schema.String() != "" {
+		t.Errorf("Expected an empty schema, got %v", schema)
+	}
+	if got := len(From([]Person{}).Schema().Columns); got != 5 {
+		t.Errorf("Expected struct columns without rows, got %d", got)
+	}
+
+	schema = FromRecords([]map[string]any{{"x": math.NaN()}, {"x": 1}}).Schema()
+	expected := []Column{{Name: "x", Header: "x", Type: reflect.TypeOf(0.0)}}
+	if !reflect.DeepEqual(schema.Columns, expected) {
+		t.Errorf("Expected NaN to count as a float, got %v", schema.Columns)
+	}
+}
+
+func TestSchemaStruct(t *testing.T) {
+	owner := "Alice"
+	accounts := []SchemaAccount{
+		{"Main", 100, &owner, nil, "x"},
+		{"Savings", 500, nil, []string{"long-term"}, "y"},
+	}
+
+	schema := From(accounts).Schema()
+	names := make([]string, len(schema.Columns))
+	for i, col := range schema.Columns {
+		names[i] = col.Name
+	}
+	if !reflect.DeepEqual(names, []string{"Name", "Balance", "Owner", "Tags"}) {
+		t.Errorf("Expected exported fields in order, got %v", names)
+	}
+
+	owners, _ := schema.Column("Owner")
+	if !owners.Nullable || owners.Nulls != 1 || owners.Type != reflect.TypeOf(&owner) {
+		t.Errorf("Unexpected Owner column %v", owners)
+	}
+	balance, _ := schema.Column("Balance")
+	if balance.Nullable || balance.Type != reflect.TypeOf(0.0) {
+		t.Errorf("Unexpected Balance column %v", balance)
+	}
+	if len(schema.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", schema.Warnings)
+	}
+
+	if cols := From([]SchemaAccount{}).Schema().Columns; len(cols) != 4 {
+		t.Errorf("Expected schema of empty pipeline from type, got %v", cols)
+	}
+}
+
+func TestSchemaStructTags(t *testing.T) {
+	n := 12
+	events := From([]CSVEvent{{Title: "Launch", Attendee: &n}, {Title: "Retro"}})
+
+	schema := events.Schema()
+	names := make([]string, len(schema.Columns))
+	headers := make([]string, len(schema.Columns))
+	for i, col := range schema.Columns {
+		names[i] = col.Name
+		headers[i] = col.Header
+	}
+
+	var buf strings.Builder
+	if err := events.WriteCSV(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	header := strings.SplitN(buf.String(), "\n", 2)[0]
+	if strings.Join(headers, ",") != header {
+		t.Errorf("Expected schema headers to match CSV header %q, got %v", header, headers)
+	}
+	if !reflect.DeepEqual(names, []string{"Title", "Attendee", "When", "Length"}) {
+		t.Errorf("Expected Go field names, got %v", names)
+	}
+	if row := events.Select(names...).Collect()[0]; len(row) != 4 || row["Attendee"] != &n {
+		t.Errorf("Expected schema names to work with Select, got %v", row)
+	}
+	if got := len(events.Where(names[0]).Equals("Retro").Collect()); got != 1 {
+		t.Errorf("Expected schema names to work with Where, got %d rows", got)
+	}
+
+	attendees, ok := schema.Column("attendees")
+	if !ok || attendees.Nulls != 1 {
+		t.Errorf("Expected tagged pointer column with one null, got %v", attendees)
+	}
+	if _, ok := schema.Column("Internal"); ok {
+		t.Error("Expected fields tagged \"-\" to be left out")
+	}
+}
@@ -0,0 +1,161 @@
+package plygo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Concat appends pipelines in order. Rows keep their original indices, and
+// Sources reports which argument (1-based) each row came from.
+func Concat[T any](pipelines ...*Pipeline[T]) *Pipeline[T] {
+	result := make([]T, 0)
+	resultIdx := make([]int, 0)
+	resultSrc := make([]int, 0)
+	var columns []string
+	ordered := true
+
+	for n, p := range pipelines {
+		result = append(result, p.data...)
+		for i := range p.data {
+			idx := 0
+			if i < len(p.originalIndex) {
+				idx = p.originalIndex[i]
+			}
+			resultIdx = append(resultIdx, idx)
+			resultSrc = append(resultSrc, n+1)
+		}
+		columns = mergeColumns(columns, p.columns)
+		ordered = ordered && (p.columns != nil || len(p.data) == 0)
+	}
+	if !ordered {
+		columns = nil
+	}
+
+	return &Pipeline[T]{data: result, originalIndex: resultIdx, columns: columns, sources: resultSrc}
+}
+
+// Sources returns, for each row, the 1-based position of the pipeline it
+// came from in Concat or Union. Rows that were never combined report
+// source 1.
+func (p *Pipeline[T]) Sources() []int {
+	result := make([]int, len(p.data))
+	for i := range result {
+		result[i] = 1
+		if i < len(p.sources) {
+			result[i] = p.sources[i]
+		}
+	}
+	return result
+}
+
+// Union concatenates other after p and removes duplicates, keeping the first
+// occurrence. Rows are compared by the given fields, or by every field when
+// none are given.
+func (p *Pipeline[T]) Union(other *Pipeline[T], fields ...string) *Pipeline[T] {
+	combined := Concat(p, other)
+
+	seen := make(map[string]bool)
+	positions := make([]int, 0)
+	for i, item := range combined.data {
+		key := setKey(item, fields)
+		if !seen[key] {
+			seen[key] = true
+			positions = append(positions, i)
+		}
+	}
+	return combined.subset(positions)
+}
+
+// Intersect keeps the distinct rows of p that also appear in other.
+func (p *Pipeline[T]) Intersect(other *Pipeline[T], fields ...string) *Pipeline[T] {
+	return p.setFilter(other, fields, true)
+}
+
+// Except keeps the distinct rows of p that do not appear in other.
+func (p *Pipeline[T]) Except(other *Pipeline[T], fields ...string) *Pipeline[T] {
+	return p.setFilter(other, fields, false)
+}
+
+func (p *Pipeline[T]) setFilter(other *Pipeline[T], fields []string, keep bool) *Pipeline[T] {
+	present := make(map[string]bool, len(other.data))
+	for _, item := range other.data {
+		present[setKey(item, fields)] = true
+	}
+
+	seen := make(map[string]bool)
+	positions := make([]int, 0)
+	for i, item := range p.data {
+		key := setKey(item, fields)
+		if present[key] == keep && !seen[key] {
+			seen[key] = true
+			positions = append(positions, i)
+		}
+	}
+	return p.subset(positions)
+}
+
+// setKey identifies a row by the given fields, or by all of its fields when
+// none are given. Values are normalized like join keys: pointers are
+// compared by the values they point to and numbers by value, whatever their
+// type.
+func setKey(item any, fields []string) string {
+	if len(fields) == 0 {
+		fields = rowFields(item)
+	}
+	if len(fields) == 0 {
+		key := joinValue(item)
+		return fmt.Sprintf("%T:%#v", key, key)
+	}
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		key := joinValue(getFieldValue(item, field))
+		parts[i] = fmt.Sprintf("%T:%#v", key, key)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// rowFields lists the exported fields of a struct row or the sorted keys of
+// a map row. It returns nil for any other value.
+func rowFields(item any) []string {
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	var fields []string
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if sf := v.Type().Field(i); sf.IsExported() {
+				fields = append(fields, sf.Name)
+			}
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			if k.Kind() == reflect.String {
+				fields = append(fields, k.String())
+			}
+		}
+		sort.Strings(fields)
+	}
+	return fields
+}
+
+func mergeColumns(columns, more []string) []string {
+	for _, col := range more {
+		found := false
+		for _, existing := range columns {
+			if existing == col {
+				found = true
+				break
+			}
+		}
+		if !found {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
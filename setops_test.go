@@ -0,0 +1,141 @@
+package plygo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	people := From(samplePeople()[:4])
+
+	combined := Concat(
+		people.AtRow(2, 4),
+		people.WhereEvery(W[Person]("City").Equals("NYC")),
+	)
+
+	if combined.Count() != 4 {
+		t.Fatalf("Expected 4 rows, got %d", combined.Count())
+	}
+	if !reflect.DeepEqual(combined.Which(), []int{2, 4, 1, 3}) {
+		t.Errorf("Expected original indices [2 4 1 3], got %v", combined.Which())
+	}
+	if !reflect.DeepEqual(combined.Sources(), []int{1, 1, 2, 2}) {
+		t.Errorf("Expected sources [1 1 2 2], got %v", combined.Sources())
+	}
+
+	filtered := combined.WhereEvery(W[Person]("Age").GreaterThan(27)).Limit(2)
+	if !reflect.DeepEqual(filtered.Sources(), []int{1, 2}) {
+		t.Errorf("Expected sources to follow rows, got %v", filtered.Sources())
+	}
+}
+
+func TestSources_Uncombined(t *testing.T) {
+	if !reflect.DeepEqual(From(samplePeople()[:4]).Tail(2).Sources(), []int{1, 1}) {
+		t.Error("Expected source 1 for uncombined rows")
+	}
+}
+
+func TestUnion(t *testing.T) {
+	people := From(samplePeople()[:4])
+
+	whole := people.AtRow(1, 2).Union(people.AtRow(2, 3))
+	if !reflect.DeepEqual(whole.Which(), []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", whole.Which())
+	}
+	if !reflect.DeepEqual(whole.Sources(), []int{1, 1, 2}) {
+		t.Errorf("Expected sources [1 1 2], got %v", whole.Sources())
+	}
+
+	byCity := people.AtRow(1).Union(people, "City")
+	if byCity.Count() != 3 {
+		t.Errorf("Expected one row per city, got %d", byCity.Count())
+	}
+}
+
+func TestIntersectExcept(t *testing.T) {
+	people := From(samplePeople()[:4])
+	nyc := people.WhereEvery(W[Person]("City").Equals("NYC"))
+
+	both := people.Intersect(nyc)
+	if !reflect.DeepEqual(both.Which(), []int{1, 3}) {
+		t.Errorf("Expected [1 3], got %v", both.Which())
+	}
+
+	rest := people.Except(nyc, "City")
+	if !reflect.DeepEqual(rest.Which(), []int{2, 4}) {
+		t.Errorf("Expected [2 4], got %v", rest.Which())
+	}
+}
+
+func TestSetOps_MapPipelines(t *testing.T) {
+	a := From([]map[string]any{{"id": 1, "v": "x"}, {"id": 2, "v": "y"}})
+	b := From([]map[string]any{{"id": 2, "v": "y"}, {"id": 3, "v": "z"}})
+
+	if a.Union(b).Count() != 3 {
+		t.Errorf("Expected 3 rows in union, got %d", a.Union(b).Count())
+	}
+	if a.Intersect(b, "id").Count() != 1 {
+		t.Errorf("Expected 1 row in intersection, got %d", a.Intersect(b, "id").Count())
+	}
+	if got := a.Except(b).Collect(); len(got) != 1 || got[0]["id"] != 1 {
+		t.Errorf("Expected only id 1, got %v", got)
+	}
+	if Concat(a, b).Count() != 4 {
+		t.Errorf("Expected 4 rows in concat, got %d", Concat(a, b).Count())
+	}
+}
+
+func TestSetOps_MixedNumericAndEmpty(t *testing.T) {
+	a := From([]map[string]any{{"id": 1, "v": "x"}, {"id": 2, "v": "y"}, {"id": 2.5, "v": "z"}})
+	b := From([]map[string]any{{"id": int64(2), "v": "y"}, {"id": 2.5, "v": "z"}, {"id": uint8(3), "v": "w"}})
+
+	if got := a.Intersect(b).Which(); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("Expected int and int64 ids to match, got %v", got)
+	}
+	if got := a.Union(b, "id").Count(); got != 4 {
+		t.Errorf("Expected 4 distinct ids, got %d", got)
+	}
+
+	none := From([]Person{})
+	people := From(samplePeople()[:4])
+	if none.Union(none).Count() != 0 || none.Intersect(people).Count() != 0 || Concat(none, none).Count() != 0 {
+		t.Error("Expected empty results from empty inputs")
+	}
+	if got := people.Except(none); !reflect.DeepEqual(got.Which(), []int{1, 2, 3, 4}) {
+		t.Errorf("Expected every row to survive Except with an empty pipeline, got %v", got.Which())
+	}
+}
+
+func TestSetOps_PointerFields(t *testing.T) {
+	type tagged struct {
+		Name  string
+		Score *int
+		Note  *string
+	}
+	ptr := func(v int) *int { return &v }
+	note := func(s string) *string { return &s }
+	rows := From([]tagged{
+		{"a", ptr(1), note("x")},
+		{"a", ptr(1), note("x")},
+		{"b", ptr(2), nil},
+		{"b", ptr(2), nil},
+	})
+
+	if !reflect.DeepEqual(rows.DistinctAll().Which(), []int{1, 3}) {
+		t.Errorf("Expected rows with equal pointees to be duplicates, got %v", rows.DistinctAll().Which())
+	}
+	if rows.Duplicates("Score").Count() != 4 {
+		t.Errorf("Expected every row to be duplicated by Score, got %d", rows.Duplicates("Score").Count())
+	}
+	if rows.AtRow(1).Union(rows.AtRow(2, 3)).Count() != 2 {
+		t.Error("Expected union to drop the row with equal pointees")
+	}
+	if rows.AtRow(1, 3).Intersect(rows.AtRow(2)).Count() != 1 || rows.AtRow(1).Except(rows.AtRow(2)).Count() != 0 {
+		t.Error("Expected intersect and except to compare pointees")
+	}
+
+	got := rows.Window().PartitionBy("Score", "Note").Compute(RowNumber()).Collect()
+	if got[1]["RowNumber"] != 2 || got[3]["RowNumber"] != 2 {
+		t.Errorf("Expected two rows per partition, got %v", got)
+	}
+}
@@ -2,6 +2,8 @@ package plygo
 
 import (
 "fmt"
+"io"
+"os"
 "sort"
 "reflect"
 "strings"
@@ -19,6 +21,7 @@ showOriginalIdx  bool
 floatPrecision   int
 boolStyle        string
 compact          bool
+out              io.Writer
 }
 
 type ShowOption func(*ShowConfig)
@@ -63,6 +66,17 @@ func WithCompact(compact bool) ShowOption {
 return func(c *ShowConfig) { c.compact = compact }
 }
 
+// WithWriter sends the output of Show and its relatives to w instead of
+// standard output.
+func WithWriter(w io.Writer) ShowOption {
+return func(c *ShowConfig) { c.out = w }
+}
+
+// withWriter appends WithWriter(w) without modifying the caller's slice.
+func withWriter(w io.Writer, options []ShowOption) []ShowOption {
+return append(options[:len(options):len(options)], WithWriter(w))
+}
+
 func defaultShowConfig() *ShowConfig {
 return &ShowConfig{
 maxRows:        20,
@@ -72,6 +86,7 @@ style:          "simple",
 floatPrecision: 2,
 boolStyle:      "text",
 compact:        false,
+out:            os.Stdout,
 }
 }
 
@@ -129,11 +144,23 @@ opt(config)
 }
 
 if len(p.data) == 0 {
-fmt.Println("Empty dataset")
+printNote(config, "Empty dataset")
 return
 }
 
-showTable(p.data, p.originalIndex, config)
+showTable(p.data, p.originalIndex, p.columns, config)
+}
+
+// Fprint writes the Show output to w.
+func (p *Pipeline[T]) Fprint(w io.Writer, options ...ShowOption) {
+p.Show(withWriter(w, options)...)
+}
+
+// Render returns the Show output as a string.
+func (p *Pipeline[T]) Render(options ...ShowOption) string {
+var b strings.Builder
+p.Fprint(&b, options...)
+return b.String()
 }
 
 func (s *Selection[T]) Show(options ...ShowOption) {
@@ -144,11 +171,21 @@ opt(config)
 
 data := s.Collect()
 if len(data) == 0 {
-fmt.Println("Empty dataset")
+printNote(config, "Empty dataset")
 return
 }
 
-showMapTable(data, s.pipeline.originalIndex, config)
+showMapTable(data, s.pipeline.originalIndex, s.fields, config)
+}
+
+func (s *Selection[T]) Fprint(w io.Writer, options ...ShowOption) {
+s.Show(withWriter(w, options)...)
+}
+
+func (s *Selection[T]) Render(options ...ShowOption) string {
+var b strings.Builder
+s.Fprint(&b, options...)
+return b.String()
 }
 
 func ShowPositions(pos PositionIndex, options ...ShowOption) {
@@ -158,13 +195,13 @@ opt(config)
 }
 
 if len(pos.Rows) == 0 {
-fmt.Println("No positions")
+printNote(config, "No positions")
 return
 }
 
-fmt.Printf("Original Positions: %v\n", pos.Rows)
+printNote(config, "Original Positions: %v", pos.Rows)
 if len(pos.Cols) > 0 {
-fmt.Printf("Column Positions: %v\n", pos.Cols)
+printNote(config, "Column Positions: %v", pos.Cols)
 }
 
 style := getTableStyle(config.style)
@@ -192,11 +229,28 @@ rows = append(rows, []string{fmt.Sprintf("%d", i+1), fmt.Sprintf("%d", row)})
 renderTable(headers, rows, style, config)
 }
 
-func showTable[T any](data []T, originalIndex []int, config *ShowConfig) {
+// Fprint writes the ShowPositions output to w.
+func (pi PositionIndex) Fprint(w io.Writer, options ...ShowOption) {
+ShowPositions(pi, withWriter(w, options)...)
+}
+
+// Render returns the ShowPositions output as a string.
+func (pi PositionIndex) Render(options ...ShowOption) string {
+var b strings.Builder
+pi.Fprint(&b, options...)
+return b.String()
+}
+
+func showTable[T any](data []T, originalIndex []int, columns []string, config *ShowConfig) {
 if len(data) == 0 {
 return
 }
 
+if rows, ok := any(data).([]map[string]any); ok {
+showMapTable(rows, originalIndex, columns, config)
+return
+}
+
 headers, rows := extractStructData(data, originalIndex, config)
 style := getTableStyle(config.style)
 
@@ -208,19 +262,19 @@ truncatedRows, omitted := truncateRows(rows, config.maxRows)
 renderTable(headers, truncatedRows, style, config)
 
 if omitted > 0 {
-fmt.Printf("[%d rows × %d columns] (showing %d of %d)\n", 
-len(rows), len(headers), len(truncatedRows), len(rows))
+printNote(config, "[%d rows × %d columns] (showing %d of %d)", 
+len(rows), len(headers), len(rows)-omitted, len(rows))
 } else {
-fmt.Printf("[%d rows × %d columns]\n", len(rows), len(headers))
+printNote(config, "[%d rows × %d columns]", len(rows), len(headers))
 }
 }
 
-func showMapTable(data []map[string]any, originalIndex []int, config *ShowConfig) {
+func showMapTable(data []map[string]any, originalIndex []int, fields []string, config *ShowConfig) {
 if len(data) == 0 {
 return
 }
 
-headers, rows := extractMapData(data, originalIndex, config)
+headers, rows := extractMapData(data, originalIndex, fields, config)
 style := getTableStyle(config.style)
 
 if config.title != "" {
@@ -231,10 +285,10 @@ truncatedRows, omitted := truncateRows(rows, config.maxRows)
 renderTable(headers, truncatedRows, style, config)
 
 if omitted > 0 {
-fmt.Printf("[%d rows × %d columns] (showing %d of %d)\n", 
-len(rows), len(headers), len(truncatedRows), len(rows))
+printNote(config, "[%d rows × %d columns] (showing %d of %d)", 
+len(rows), len(headers), len(rows)-omitted, len(rows))
 } else {
-fmt.Printf("[%d rows × %d columns]\n", len(rows), len(headers))
+printNote(config, "[%d rows × %d columns]", len(rows), len(headers))
 }
 }
 
@@ -292,7 +346,7 @@ rows = append(rows, row)
 return headers, rows
 }
 
-func extractMapData(data []map[string]any, originalIndex []int, config *ShowConfig) ([]string, [][]string) {
+func extractMapData(data []map[string]any, originalIndex []int, fields []string, config *ShowConfig) ([]string, [][]string) {
 if len(data) == 0 {
 return nil, nil
 }
@@ -302,11 +356,10 @@ if config.showRowNumbers || config.showOriginalIdx {
 headers = append(headers, "#")
 }
 
-fieldOrder := make([]string, 0)
-for key := range data[0] {
-fieldOrder = append(fieldOrder, key)
+fieldOrder := fields
+if len(fieldOrder) == 0 {
+fieldOrder = mapKeys(data)
 }
-sort.Strings(fieldOrder)
 headers = append(headers, fieldOrder...)
 
 rows := make([][]string, 0, len(data))
@@ -329,6 +382,21 @@ rows = append(rows, row)
 return headers, rows
 }
 
+func mapKeys(data []map[string]any) []string {
+seen := make(map[string]bool)
+keys := make([]string, 0)
+for _, item := range data {
+for key := range item {
+if !seen[key] {
+seen[key] = true
+keys = append(keys, key)
+}
+}
+}
+sort.Strings(keys)
+return keys
+}
+
 func formatValue(v any, config *ShowConfig) string {
 if v == nil {
 return "nil"
@@ -355,15 +423,18 @@ return fmt.Sprintf("%v", val)
 }
 }
 
+// truncateRows keeps the first and last rows when there are more than
+// maxRows, with a "..." row between them. The extra row of an odd maxRows
+// goes to the top. maxRows <= 0 means no limit.
 func truncateRows(rows [][]string, maxRows int) ([][]string, int) {
-if len(rows) <= maxRows {
+if maxRows <= 0 || len(rows) <= maxRows {
 return rows, 0
 }
 
 half := maxRows / 2
 result := make([][]string, 0, maxRows+1)
 
-result = append(result, rows[:half]...)
+result = append(result, rows[:maxRows-half]...)
 
 if len(rows[0]) > 0 {
 separator := make([]string, len(rows[0]))
@@ -383,35 +454,40 @@ if len(headers) == 0 {
 return
 }
 
+if config.style == "html" {
+renderHTMLTable(config.out, headers, rows, config)
+return
+}
+
 colWidths := calculateColumnWidths(headers, rows, config.maxColWidth)
 
 if style.topLeft == "" {
-renderMinimalTable(headers, rows, colWidths)
+renderMinimalTable(config.out, headers, rows, colWidths)
 return
 }
 
-printTopBorder(colWidths, style)
-printRow(headers, colWidths, style, true)
-printHeaderSeparator(colWidths, style)
+printTopBorder(config.out, colWidths, style)
+printRow(config.out, headers, colWidths, style, true)
+printHeaderSeparator(config.out, colWidths, style)
 
 for _, row := range rows {
-printRow(row, colWidths, style, false)
+printRow(config.out, row, colWidths, style, false)
 }
 
-printBottomBorder(colWidths, style)
+printBottomBorder(config.out, colWidths, style)
 }
 
-func renderMinimalTable(headers []string, rows [][]string, colWidths []int) {
-printMinimalRow(headers, colWidths, true)
+func renderMinimalTable(w io.Writer, headers []string, rows [][]string, colWidths []int) {
+printMinimalRow(w, headers, colWidths, true)
 
 totalWidth := 0
 for _, w := range colWidths {
 totalWidth += w + 2
 }
-fmt.Println(strings.Repeat("─", totalWidth-2))
+fmt.Fprintln(w, strings.Repeat("─", totalWidth-2))
 
 for _, row := range rows {
-printMinimalRow(row, colWidths, false)
+printMinimalRow(w, row, colWidths, false)
 }
 }
 
@@ -443,61 +519,64 @@ return widths
 }
 
 func printTitle(title string, config *ShowConfig) {
-fmt.Println()
-fmt.Printf("%s%s\n", strings.Repeat(" ", 8), title)
+if config.style == "html" {
+return
+}
+fmt.Fprintln(config.out)
+fmt.Fprintf(config.out, "%s%s\n", strings.Repeat(" ", 8), title)
 }
 
-func printTopBorder(widths []int, style tableStyle) {
+func printTopBorder(w io.Writer, widths []int, style tableStyle) {
 if style.topLeft == "" {
 return
 }
 
-fmt.Print(style.topLeft)
+fmt.Fprint(w, style.topLeft)
 for i, width := range widths {
-fmt.Print(strings.Repeat(style.horizontal, width+2))
+fmt.Fprint(w, strings.Repeat(style.horizontal, width+2))
 if i < len(widths)-1 {
-fmt.Print(style.topCross)
+fmt.Fprint(w, style.topCross)
 }
 }
-fmt.Println(style.topRight)
+fmt.Fprintln(w, style.topRight)
 }
 
-func printBottomBorder(widths []int, style tableStyle) {
+func printBottomBorder(w io.Writer, widths []int, style tableStyle) {
 if style.bottomLeft == "" {
 return
 }
 
-fmt.Print(style.bottomLeft)
+fmt.Fprint(w, style.bottomLeft)
 for i, width := range widths {
-fmt.Print(strings.Repeat(style.horizontal, width+2))
+fmt.Fprint(w, strings.Repeat(style.horizontal, width+2))
 if i < len(widths)-1 {
-fmt.Print(style.bottomCross)
+fmt.Fprint(w, style.bottomCross)
 }
 }
-fmt.Println(style.bottomRight)
+fmt.Fprintln(w, style.bottomRight)
 }
 
-func printHeaderSeparator(widths []int, style tableStyle) {
+func printHeaderSeparator(w io.Writer, widths []int, style tableStyle) {
 if style.headerSep == "" {
 return
 }
 
-fmt.Print(style.headerSep)
+fmt.Fprint(w, style.headerSep)
 for i, width := range widths {
-fmt.Print(strings.Repeat(style.horizontal, width+2))
+fmt.Fprint(w, strings.Repeat(style.horizontal, width+2))
 if i < len(widths)-1 {
-fmt.Print(style.cross)
+fmt.Fprint(w, style.cross)
 }
 }
 if style.headerSep == "|" {
-fmt.Println(style.headerSep)
+fmt.Fprintln(w, style.headerSep)
 } else {
-fmt.Println(style.rightCross)
+fmt.Fprintln(w, style.rightCross)
 }
 }
 
-func printRow(cells []string, widths []int, style tableStyle, isHeader bool) {
-fmt.Print(style.vertical)
+func printRow(w io.Writer, cells []string, widths []int, style tableStyle, isHeader bool) {
+fmt.Fprint(w, style.vertical)
 
 for i, width := range widths {
 cell := ""
@@ -508,13 +587,13 @@ cell = cells[i]
 cell = truncateString(cell, width)
 
 aligned := alignCell(cell, width, isHeader || isNumeric(cell))
-fmt.Printf(" %s ", aligned)
-fmt.Print(style.vertical)
+fmt.Fprintf(w, " %s ", aligned)
+fmt.Fprint(w, style.vertical)
 }
-fmt.Println()
+fmt.Fprintln(w)
 }
 
-func printMinimalRow(cells []string, widths []int, isHeader bool) {
+func printMinimalRow(w io.Writer, cells []string, widths []int, isHeader bool) {
 for i, width := range widths {
 cell := ""
 if i < len(cells) {
@@ -523,13 +602,13 @@ cell = cells[i]
 
 cell = truncateString(cell, width)
 aligned := alignCell(cell, width, isHeader || isNumeric(cell))
-fmt.Printf("%s", aligned)
+fmt.Fprintf(w, "%s", aligned)
 
 if i < len(widths)-1 {
-fmt.Print("  ")
+fmt.Fprint(w, "  ")
 }
 }
-fmt.Println()
+fmt.Fprintln(w)
 }
 
 func alignCell(cell string, width int, rightAlign bool) string {
@@ -582,38 +661,56 @@ opt(config)
 }
 
 if len(filtered) == 0 {
-fmt.Println("Empty dataset")
+printNote(config, "Empty dataset")
 return
 }
 
 indices := c.Positions().Rows
-showTable(filtered, indices, config)
+showTable(filtered, indices, c.pipeline.columns, config)
+}
+
+func (c *Condition[T]) Fprint(w io.Writer, options ...ShowOption) {
+c.Show(withWriter(w, options)...)
+}
+
+func (c *Condition[T]) Render(options ...ShowOption) string {
+var b strings.Builder
+c.Fprint(&b, options...)
+return b.String()
 }
 
 func (s *Sorter[T]) Show(options ...ShowOption) {
-sorted := s.Collect()
+sorted := s.executePipeline()
 config := defaultShowConfig()
 for _, opt := range options {
 opt(config)
 }
 
-if len(sorted) == 0 {
-fmt.Println("Empty dataset")
+if len(sorted.data) == 0 {
+printNote(config, "Empty dataset")
 return
 }
 
-showTable(sorted, s.pipeline.originalIndex, config)
+showTable(sorted.data, sorted.originalIndex, sorted.columns, config)
 }
 
 
+func (s *Sorter[T]) Fprint(w io.Writer, options ...ShowOption) {
+s.Show(withWriter(w, options)...)
+}
+
+func (s *Sorter[T]) Render(options ...ShowOption) string {
+var b strings.Builder
+s.Fprint(&b, options...)
+return b.String()
+}
+
 func (s *Sorter[T]) AtRow(indices ...int) *Pipeline[T] {
-sorted := s.Collect()
-return From(sorted).AtRow(indices...)
+return s.executePipeline().AtRow(indices...)
 }
 
 func (s *Sorter[T]) AtCol(indices ...int) *Selection[T] {
-sorted := s.Collect()
-return From(sorted).AtCol(indices...)
+return s.executePipeline().AtCol(indices...)
 }
 
 
@@ -215,6 +215,46 @@ t.Error("Should indicate truncation in footer")
 }
 }
 
+func TestShow_OddMaxRows(t *testing.T) {
+people := make([]ShowTestPerson, 9)
+for i := range people {
+people[i] = ShowTestPerson{Name: fmt.Sprintf("Person%d", i+1), Age: 20 + i}
+}
+
+for _, maxRows := range []int{1, 5} {
+output := From(people).Render(WithMaxRows(maxRows))
+shown := strings.Count(output, "Person")
+if shown != maxRows {
+t.Errorf("MaxRows %d: expected %d rows printed, got %d", maxRows, maxRows, shown)
+}
+if !strings.Contains(output, fmt.Sprintf("(showing %d of 9)", maxRows)) {
+t.Errorf("MaxRows %d: summary disagrees with printed rows:\n%s", maxRows, output)
+}
+}
+
+output := From(people).Render(WithMaxRows(5))
+if !strings.Contains(output, "Person3") || strings.Contains(output, "Person4") || !strings.Contains(output, "Person8") {
+t.Errorf("Expected the first three and last two rows, got:\n%s", output)
+}
+}
+
+func TestShow_MaxRowsUnlimited(t *testing.T) {
+people := make([]ShowTestPerson, 30)
+for i := range people {
+people[i] = ShowTestPerson{Name: fmt.Sprintf("Person%d", i+1), Age: 20 + i}
+}
+
+for _, maxRows := range []int{0, -1} {
+output := From(people).Render(WithMaxRows(maxRows))
+if shown := strings.Count(output, "Person"); shown != 30 {
+t.Errorf("MaxRows %d: expected every row, got %d", maxRows, shown)
+}
+if strings.Contains(output, "...") || !strings.Contains(output, "[30 rows × 5 columns]\n") {
+t.Errorf("MaxRows %d: expected no truncation:\n%s", maxRows, output)
+}
+}
+}
+
 func TestShow_EmptyDataset(t *testing.T) {
 people := []ShowTestPerson{}
 
@@ -415,3 +455,63 @@ if !strings.Contains(output, "1") || !strings.Contains(output, "5") {
 t.Error("Should show simple type values")
 }
 }
+
+func TestRender_MatchesShow(t *testing.T) {
+people := []ShowTestPerson{
+{"Alice", 30, "NYC", 75000.50, true},
+{"Bob", 25, "LA", 60000.00, false},
+}
+options := []ShowOption{WithTitle("People"), WithStyle("rounded")}
+
+shown := captureOutput(func() {
+From(people).Show(options...)
+})
+rendered := From(people).Render(options...)
+
+if rendered != shown {
+t.Errorf("Render should match Show output:\n%s\n%s", rendered, shown)
+}
+if len(options) != 2 {
+t.Error("Render should not modify the caller's options")
+}
+}
+
+func TestFprint_AllShowableTypes(t *testing.T) {
+people := []ShowTestPerson{
+{"Alice", 30, "NYC", 75000, true},
+{"Bob", 25, "LA", 60000, false},
+}
+p := From(people)
+
+outputs := map[string][2]string{
+"pipeline":  {p.Render(), "2 rows"},
+"selection": {p.Select("Name", "City").Render(), "NYC"},
+"condition": {p.Where("Age").GreaterThan(26).Render(), "1 rows"},
+"sorter":    {p.OrderBy("Age").Render(), "Bob"},
+"positions": {p.Where("Age").GreaterThan(26).Positions().Render(), "Original Positions: [1]"},
+"histogram": {Histogram{Edges: []float64{0, 1}, Counts: []int{3}}.Render(), "[3 values in 1 bins]"},
+}
+for name, out := range outputs {
+if !strings.Contains(out[0], out[1]) {
+t.Errorf("%s: expected %q in %q", name, out[1], out[0])
+}
+}
+
+var buf bytes.Buffer
+p.Where("Age").GreaterThan(100).Fprint(&buf)
+if buf.String() != "Empty dataset\n" {
+t.Errorf("Expected empty dataset message, got %q", buf.String())
+}
+
+stdout := captureOutput(func() {
+buf.Reset()
+p.Show(WithWriter(&buf), WithStyle("minimal"))
+p.OrderBy("Age").Fprint(&buf)
+})
+if stdout != "" {
+t.Errorf("Expected nothing on stdout, got %q", stdout)
+}
+if strings.Count(buf.String(), "Alice") != 2 {
+t.Error("Expected both tables in the writer")
+}
+}
@@ -0,0 +1,145 @@
+package plygo
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// A small implementation of the snappy block format, used for Parquet pages.
+
+var errSnappy = errors.New("malformed snappy data")
+
+const maxSnappyLen = 1 << 30
+
+func snappyEncode(src []byte) []byte {
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)/2+16), uint64(len(src)))
+
+	var table [1 << 14]int32
+	hash := func(u uint32) uint32 { return (u * 0x1e35a7bd) >> 18 }
+
+	lit := 0
+	for i := 0; i+4 <= len(src); {
+		h := hash(binary.LittleEndian.Uint32(src[i:]))
+		cand := int(table[h]) - 1
+		table[h] = int32(i + 1)
+
+		if cand < 0 || i-cand > 0xffff ||
+			binary.LittleEndian.Uint32(src[cand:]) != binary.LittleEndian.Uint32(src[i:]) {
+			i++
+			continue
+		}
+
+		n := 4
+		for i+n < len(src) && src[cand+n] == src[i+n] {
+			n++
+		}
+		dst = snappyLiteral(dst, src[lit:i])
+		dst = snappyCopy(dst, i-cand, n)
+		i += n
+		lit = i
+	}
+	return snappyLiteral(dst, src[lit:])
+}
+
+func snappyLiteral(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	n := len(lit) - 1
+	switch {
+	case n < 60:
+		dst = append(dst, byte(n<<2))
+	case n < 1<<8:
+		dst = append(dst, 60<<2, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, lit...)
+}
+
+// snappyCopy emits copies with 2-byte offsets, each at most 64 bytes long.
+func snappyCopy(dst []byte, offset, length int) []byte {
+	for length > 0 {
+		n := min(length, 64)
+		if length > 64 && length < 68 {
+			n = 60
+		}
+		dst = append(dst, byte((n-1)<<2|2), byte(offset), byte(offset>>8))
+		length -= n
+	}
+	return dst
+}
+
+func snappyDecode(src []byte) ([]byte, error) {
+	size, k := binary.Uvarint(src)
+	if k <= 0 || size > maxSnappyLen {
+		return nil, errSnappy
+	}
+	dst := make([]byte, 0, size)
+
+	for s := k; s < len(src); {
+		tag := src[s]
+		var length, offset int
+
+		switch tag & 3 {
+		case 0:
+			length = int(tag >> 2)
+			s++
+			if length >= 60 {
+				nb := length - 59
+				if s+nb > len(src) {
+					return nil, errSnappy
+				}
+				length = 0
+				for j := nb - 1; j >= 0; j-- {
+					length = length<<8 | int(src[s+j])
+				}
+				s += nb
+			}
+			length++
+			if length > len(src)-s {
+				return nil, errSnappy
+			}
+			dst = append(dst, src[s:s+length]...)
+			s += length
+			continue
+		case 1:
+			if s+2 > len(src) {
+				return nil, errSnappy
+			}
+			length = int(tag>>2&7) + 4
+			offset = int(tag&0xe0)<<3 | int(src[s+1])
+			s += 2
+		case 2:
+			if s+3 > len(src) {
+				return nil, errSnappy
+			}
+			length = int(tag>>2) + 1
+			offset = int(binary.LittleEndian.Uint16(src[s+1:]))
+			s += 3
+		case 3:
+			if s+5 > len(src) {
+				return nil, errSnappy
+			}
+			length = int(tag>>2) + 1
+			offset = int(binary.LittleEndian.Uint32(src[s+1:]))
+			s += 5
+		}
+
+		if offset <= 0 || offset > len(dst) || uint64(len(dst)+length) > size {
+			return nil, errSnappy
+		}
+		for j := 0; j < length; j++ {
+			dst = append(dst, dst[len(dst)-offset])
+		}
+	}
+
+	if uint64(len(dst)) != size {
+		return nil, errSnappy
+	}
+	return dst, nil
+}
@@ -0,0 +1,67 @@
+package plygo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSnappyRoundTrip(t *testing.T) {
+	inputs := [][]byte{
+		nil,
+		[]byte("a"),
+		[]byte("abcd"),
+		[]byte(strings.Repeat("plyGO ", 1000)),
+		bytes.Repeat([]byte{0}, 70000),
+	}
+	random := make([]byte, 5000)
+	seed := uint32(1)
+	for i := range random {
+		seed = seed*1664525 + 1013904223
+		random[i] = byte(seed >> 24)
+	}
+	inputs = append(inputs, random)
+
+	for _, in := range inputs {
+		encoded := snappyEncode(in)
+		decoded, err := snappyDecode(encoded)
+		if err != nil {
+			t.Fatalf("Unexpected error for %d bytes: %v", len(in), err)
+		}
+		if !bytes.Equal(decoded, in) {
+			t.Errorf("Round trip mismatch for %d bytes", len(in))
+		}
+	}
+
+	if n := len(snappyEncode([]byte(strings.Repeat("plyGO ", 1000)))); n > 500 {
+		t.Errorf("Expected repetitive input to compress, got %d bytes", n)
+	}
+}
+
+func TestSnappyDecodeCopies(t *testing.T) {
+	// literal "ab", then a 1-byte-offset copy of length 6 overlapping itself
+	// and a 4-byte-offset copy of length 2
+	encoded := []byte{10, 1 << 2, 'a', 'b', (6-4)<<2 | 1, 2, (2-1)<<2 | 3, 4, 0, 0, 0}
+	decoded, err := snappyDecode(encoded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(decoded) != "ababababab" {
+		t.Errorf("Expected ababababab, got %q", decoded)
+	}
+}
+
+func TestSnappyDecodeMalformed(t *testing.T) {
+	cases := [][]byte{
+		{},
+		{5, 0, 'a'},
+		{2, 1 << 2, 'a'},
+		{4, 0, 'a', (4-4)<<2 | 1, 2},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+	}
+	for _, c := range cases {
+		if _, err := snappyDecode(c); err == nil {
+			t.Errorf("Expected error for %v", c)
+		}
+	}
+}
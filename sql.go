@@ -0,0 +1,256 @@
+package plygo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FromRows scans query results into a pipeline of structs, struct pointers
+// or map[string]any, and consumes rows. Columns
+// are matched to struct fields the same way FromCSV matches headers, and
+// then ignoring underscores, so created_at fills CreatedAt; unmatched
+// columns are ignored. NULLs can be scanned into pointer or
+// sql.Null* fields. For map rows every column is kept, with []byte values
+// converted to strings. The caller still owns rows and should close it.
+func FromRows[T any](rows *sql.Rows) (*Pipeline[T], error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var zero T
+	typ := reflect.TypeOf(zero)
+	structType, err := recordType(typ)
+	if err != nil {
+		return nil, err
+	}
+	isStruct := structType != nil
+
+	fieldOf := make([]int, len(columns))
+	for i := range fieldOf {
+		fieldOf[i] = -1
+	}
+	if isStruct {
+		for _, t := range matchSQLColumns(structType, columns) {
+			fieldOf[t.column] = t.field
+		}
+	}
+
+	result := make([]T, 0)
+	resultIdx := make([]int, 0)
+	dest := make([]any, len(columns))
+
+	for rows.Next() {
+		var item T
+		var v reflect.Value
+		if isStruct {
+			v = recordValue(&item)
+		}
+
+		values := make([]any, len(columns))
+		for i := range columns {
+			if isStruct && fieldOf[i] >= 0 {
+				dest[i] = v.Field(fieldOf[i]).Addr().Interface()
+			} else {
+				dest[i] = &values[i]
+			}
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("row %d: %w", len(result)+1, err)
+		}
+
+		if m, ok := any(&item).(*map[string]any); ok {
+			*m = make(map[string]any, len(columns))
+			for i, col := range columns {
+				if b, ok := values[i].([]byte); ok {
+					values[i] = string(b)
+				}
+				(*m)[col] = values[i]
+			}
+		}
+
+		result = append(result, item)
+		resultIdx = append(resultIdx, len(result))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	p := &Pipeline[T]{data: result, originalIndex: resultIdx}
+	if typ != nil && typ.Kind() == reflect.Map {
+		p.columns = columns
+	}
+	return p, nil
+}
+
+// matchSQLColumns matches columns like matchColumns, then retries the
+// unmatched ones with underscores removed, as databases use snake_case.
+func matchSQLColumns(typ reflect.Type, columns []string) []fieldTarget {
+	targets := matchColumns(typ, columns)
+	matched := make(map[int]bool, len(targets))
+	filled := make(map[int]bool, len(targets))
+	for _, t := range targets {
+		matched[t.column] = true
+		filled[t.field] = true
+	}
+
+	for c, col := range columns {
+		if matched[c] {
+			continue
+		}
+		col = strings.ReplaceAll(strings.TrimSpace(col), "_", "")
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			if !filled[i] && sf.IsExported() && fieldKey(sf) != "" && strings.EqualFold(fieldKey(sf), col) {
+				targets = append(targets, fieldTarget{column: c, field: i, name: sf.Name})
+				filled[i] = true
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// Execer is satisfied by *sql.DB, *sql.Tx and *sql.Conn. Pass a *sql.Tx to
+// InsertInto to make all batches atomic.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Dialect controls how InsertInto writes placeholders and quotes column
+// names.
+type Dialect struct {
+	Placeholder func(n int) string
+	Quote       func(ident string) string
+}
+
+var (
+	// MySQL uses ? placeholders and `backtick` quoting.
+	MySQL = Dialect{
+		Placeholder: func(int) string { return "?" },
+		Quote:       func(s string) string { return "`" + strings.ReplaceAll(s, "`", "``") + "`" },
+	}
+	// Postgres uses $1, $2... placeholders and "double quote" quoting.
+	Postgres = Dialect{
+		Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+		Quote:       quoteDouble,
+	}
+	// SQLite uses ? placeholders and "double quote" quoting.
+	SQLite = Dialect{
+		Placeholder: func(int) string { return "?" },
+		Quote:       quoteDouble,
+	}
+	// SQLServer uses @p1, @p2... placeholders and [bracket] quoting.
+	SQLServer = Dialect{
+		Placeholder: func(n int) string { return fmt.Sprintf("@p%d", n) },
+		Quote:       func(s string) string { return "[" + strings.ReplaceAll(s, "]", "]]") + "]" },
+	}
+)
+
+func quoteDouble(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+type InsertConfig struct {
+	dialect   Dialect
+	batchSize int
+	columns   []string
+}
+
+type InsertOption func(*InsertConfig)
+
+// InsertDialect sets the placeholder and quoting style. The default is
+// SQLite, whose ? placeholders also suit MySQL.
+func InsertDialect(d Dialect) InsertOption {
+	return func(c *InsertConfig) { c.dialect = d }
+}
+
+// InsertBatchSize sets the number of rows per INSERT statement (default
+// 500). Keep rows × columns under the driver's parameter limit.
+func InsertBatchSize(n int) InsertOption {
+	return func(c *InsertConfig) { c.batchSize = n }
+}
+
+// InsertColumns limits the insert to the given columns, e.g. to leave out
+// an auto-increment id. Names are the same as WriteCSV headers.
+func InsertColumns(columns ...string) InsertOption {
+	return func(c *InsertConfig) { c.columns = columns }
+}
+
+// InsertInto writes the pipeline to table with batched multi-row INSERT
+// statements and returns the number of rows affected. Columns are the
+// pipeline's fields as written by WriteCSV. The table name is used as
+// given, so it may include a schema.
+func (p *Pipeline[T]) InsertInto(ctx context.Context, db Execer, table string, options ...InsertOption) (int64, error) {
+	config := &InsertConfig{dialect: SQLite, batchSize: 500}
+	for _, opt := range options {
+		opt(config)
+	}
+	if config.batchSize <= 0 {
+		config.batchSize = 500
+	}
+
+	headers, fields := p.exportColumns()
+	if len(config.columns) > 0 {
+		byHeader := make(map[string]string, len(headers))
+		for i, h := range headers {
+			byHeader[h] = fields[i]
+		}
+		headers, fields = nil, nil
+		for _, col := range config.columns {
+			field, ok := byHeader[col]
+			if !ok {
+				return 0, fmt.Errorf("unknown column %q", col)
+			}
+			headers = append(headers, col)
+			fields = append(fields, field)
+		}
+	}
+	if len(headers) == 0 {
+		return 0, errors.New("no columns to insert")
+	}
+
+	quoted := make([]string, len(headers))
+	for i, h := range headers {
+		quoted[i] = config.dialect.Quote(h)
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", table, strings.Join(quoted, ", "))
+
+	var total int64
+	for start := 0; start < len(p.data); start += config.batchSize {
+		end := min(start+config.batchSize, len(p.data))
+
+		var query strings.Builder
+		query.WriteString(prefix)
+		args := make([]any, 0, (end-start)*len(fields))
+
+		for i, item := range p.data[start:end] {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(")
+			for j, field := range fields {
+				if j > 0 {
+					query.WriteString(", ")
+				}
+				args = append(args, getFieldValue(item, field))
+				query.WriteString(config.dialect.Placeholder(len(args)))
+			}
+			query.WriteString(")")
+		}
+
+		res, err := db.ExecContext(ctx, query.String(), args...)
+		if err != nil {
+			return total, fmt.Errorf("insert rows %d-%d: %w", start+1, end, err)
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			total += n
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,339 @@
+package plygo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubDriver is an in-memory database/sql driver. Queries return the rows
+// registered for them and every Exec is recorded.
+type stubDriver struct {
+	mu      sync.Mutex
+	results map[string]stubResult
+	execs   []stubExec
+}
+
+type stubResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+type stubExec struct {
+	query string
+	args  []driver.Value
+}
+
+var stub = &stubDriver{results: make(map[string]stubResult)}
+
+func init() {
+	sql.Register("plygo-stub", stub)
+}
+
+func (d *stubDriver) Open(string) (driver.Conn, error) { return stubConn{d}, nil }
+
+func (d *stubDriver) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.results = make(map[string]stubResult)
+	d.execs = nil
+}
+
+type stubConn struct{ d *stubDriver }
+
+func (c stubConn) Prepare(query string) (driver.Stmt, error) { return stubStmt{c.d, query}, nil }
+func (c stubConn) Close() error                              { return nil }
+func (c stubConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type stubStmt struct {
+	d     *stubDriver
+	query string
+}
+
+func (s stubStmt) Close() error  { return nil }
+func (s stubStmt) NumInput() int { return -1 }
+
+func (s stubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if strings.Contains(s.query, "fail") {
+		return nil, errors.New("table is read-only")
+	}
+	s.d.execs = append(s.d.execs, stubExec{s.query, args})
+	return driver.RowsAffected(strings.Count(s.query, "(") - 1), nil
+}
+
+func (s stubStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	res, ok := s.d.results[s.query]
+	if !ok {
+		return nil, errors.New("unknown query")
+	}
+	return &stubRows{res: res}, nil
+}
+
+type stubRows struct {
+	res stubResult
+	pos int
+}
+
+func (r *stubRows) Columns() []string { return r.res.columns }
+func (r *stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.res.rows) {
+		return io.EOF
+	}
+	copy(dest, r.res.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+type SQLUser struct {
+	ID        int64
+	Name      string `plygo:"full_name"`
+	Email     *string
+	CreatedAt string
+	Score     sql.NullFloat64
+}
+
+func openStub(t *testing.T) *sql.DB {
+	t.Helper()
+	stub.reset()
+	db, err := sql.Open("plygo-stub", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestFromRows(t *testing.T) {
+	db := openStub(t)
+	stub.results["SELECT * FROM users"] = stubResult{
+		columns: []string{"id", "full_name", "email", "created_at", "score", "ignored"},
+		rows: [][]driver.Value{
+			{int64(1), "Alice", "alice@example.com", "2024-01-02", 9.5, "x"},
+			{int64(2), []byte("Bob"), nil, "2024-02-03", nil, "y"},
+		},
+	}
+
+	rows, err := db.Query("SELECT * FROM users")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	p, err := FromRows[SQLUser](rows)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	users := p.Collect()
+	if len(users) != 2 {
+		t.Fatalf("Expected 2 users, got %d", len(users))
+	}
+	if users[0].ID != 1 || users[0].Name != "Alice" || *users[0].Email != "alice@example.com" {
+		t.Errorf("Unexpected first user %+v", users[0])
+	}
+	if users[0].CreatedAt != "2024-01-02" || users[0].Score.Float64 != 9.5 {
+		t.Errorf("Unexpected first user %+v", users[0])
+	}
+	if users[1].Name != "Bob" || users[1].Email != nil || users[1].Score.Valid {
+		t.Errorf("Expected NULLs to scan as nil, got %+v", users[1])
+	}
+	if !reflect.DeepEqual(p.Which(), []int{1, 2}) {
+		t.Errorf("Expected indices [1 2], got %v", p.Which())
+	}
+}
+
+func TestFromRowsMaps(t *testing.T) {
+	db := openStub(t)
+	stub.results["SELECT name, total FROM orders"] = stubResult{
+		columns: []string{"name", "total"},
+		rows:    [][]driver.Value{{[]byte("Alice"), 12.5}, {"Bob", nil}},
+	}
+
+	rows, err := db.Query("SELECT name, total FROM orders")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	p, err := FromRows[map[string]any](rows)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []map[string]any{{"name": "Alice", "total": 12.5}, {"name": "Bob", "total": nil}}
+	if !reflect.DeepEqual(p.Collect(), expected) {
+		t.Errorf("Expected %v, got %v", expected, p.Collect())
+	}
+	if !reflect.DeepEqual(p.FieldNames(), []string{"name", "total"}) {
+		t.Errorf("Expected column order, got %v", p.FieldNames())
+	}
+}
+
+func TestFromRowsColumnPrecedence(t *testing.T) {
+	db := openStub(t)
+	stub.results["SELECT created_at, createdat FROM users"] = stubResult{
+		columns: []string{"created_at", "createdat"},
+		rows:    [][]driver.Value{{"snake", "exact"}},
+	}
+
+	rows, err := db.Query("SELECT created_at, createdat FROM users")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	p, err := FromRows[SQLUser](rows)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := p.Collect()[0].CreatedAt; got != "exact" {
+		t.Errorf("Expected the case-insensitive match to win over the underscore match, got %q", got)
+	}
+}
+
+func TestFromRowsScanError(t *testing.T) {
+	db := openStub(t)
+	stub.results["SELECT id FROM users"] = stubResult{
+		columns: []string{"id"},
+		rows:    [][]driver.Value{{int64(1)}, {nil}},
+	}
+
+	rows, err := db.Query("SELECT id FROM users")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	_, err = FromRows[SQLUser](rows)
+	if err == nil || !strings.HasPrefix(err.Error(), "row 2:") {
+		t.Errorf("Expected scan error on row 2, got %v", err)
+	}
+}
+
+func TestFromRowsPointersAndUnsupported(t *testing.T) {
+	db := openStub(t)
+	stub.results["SELECT id, full_name FROM users"] = stubResult{
+		columns: []string{"id", "full_name"},
+		rows:    [][]driver.Value{{int64(1), "Alice"}, {int64(2), "Bob"}},
+	}
+
+	rows, err := db.Query("SELECT id, full_name FROM users")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	p, err := FromRows[*SQLUser](rows)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	users := p.Collect()
+	if len(users) != 2 || users[0] == nil || users[0].ID != 1 || users[1].Name != "Bob" {
+		t.Errorf("Expected scanned pointers, got %v", users)
+	}
+
+	rows, err = db.Query("SELECT id, full_name FROM users")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rows.Close()
+	if _, err := FromRows[string](rows); err == nil || !strings.Contains(err.Error(), "cannot read rows into string") {
+		t.Errorf("Expected unsupported type error, got %v", err)
+	}
+}
+
+func TestSQLEmpty(t *testing.T) {
+	db := openStub(t)
+	stub.results["SELECT * FROM users"] = stubResult{columns: []string{"id", "full_name"}}
+
+	rows, err := db.Query("SELECT * FROM users")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	p, err := FromRows[SQLUser](rows)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Count() != 0 {
+		t.Errorf("Expected no users, got %d", p.Count())
+	}
+
+	n, err := From([]SQLUser{}).InsertInto(context.Background(), db, "users")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 0 || len(stub.execs) != 0 {
+		t.Errorf("Expected no statements for an empty pipeline, got %d rows and %v", n, stub.execs)
+	}
+}
+
+func TestInsertInto(t *testing.T) {
+	db := openStub(t)
+	email := "alice@example.com"
+	users := []SQLUser{
+		{ID: 1, Name: "Alice", Email: &email},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Carol"},
+	}
+
+	n, err := From(users).InsertInto(context.Background(), db, "users",
+		InsertColumns("full_name", "Email"), InsertBatchSize(2), InsertDialect(Postgres))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 rows affected, got %d", n)
+	}
+
+	if len(stub.execs) != 2 {
+		t.Fatalf("Expected 2 batches, got %d", len(stub.execs))
+	}
+	expected := `INSERT INTO users ("full_name", "Email") VALUES ($1, $2), ($3, $4)`
+	if stub.execs[0].query != expected {
+		t.Errorf("Expected %q, got %q", expected, stub.execs[0].query)
+	}
+	if !reflect.DeepEqual(stub.execs[0].args, []driver.Value{"Alice", "alice@example.com", "Bob", nil}) {
+		t.Errorf("Unexpected args %v", stub.execs[0].args)
+	}
+	expected = `INSERT INTO users ("full_name", "Email") VALUES ($1, $2)`
+	if stub.execs[1].query != expected {
+		t.Errorf("Expected %q, got %q", expected, stub.execs[1].query)
+	}
+}
+
+func TestInsertIntoMapsAndErrors(t *testing.T) {
+	db := openStub(t)
+	rows := FromRecords([]map[string]any{{"a": 1, "b": "x"}, {"a": 2}})
+
+	if _, err := rows.InsertInto(context.Background(), db, "t", InsertDialect(MySQL)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "INSERT INTO t (`a`, `b`) VALUES (?, ?), (?, ?)"
+	if stub.execs[0].query != expected {
+		t.Errorf("Expected %q, got %q", expected, stub.execs[0].query)
+	}
+	if !reflect.DeepEqual(stub.execs[0].args, []driver.Value{int64(1), "x", int64(2), nil}) {
+		t.Errorf("Unexpected args %v", stub.execs[0].args)
+	}
+
+	if _, err := rows.InsertInto(context.Background(), db, "t", InsertColumns("missing")); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+	if _, err := rows.InsertInto(context.Background(), db, "fail"); err == nil || !strings.Contains(err.Error(), "insert rows 1-2") {
+		t.Errorf("Expected batch error, got %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package plygo
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// A minimal Thrift compact protocol encoder and decoder for the Parquet
+// file metadata and page headers. Structs are written from thriftFields
+// values and read back generically as field id -> value maps.
+
+const errThrift = formatError("malformed thrift metadata")
+
+const (
+	thriftStop   = 0
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStruct = 12
+
+	thriftMaxDepth = 64
+)
+
+// thriftField is one field of a struct being written. Values are bool,
+// int8, int32, int64, float64, string, []byte, thriftFields, or lists of
+// int32, string or thriftFields. Fields must be in increasing id order.
+type thriftField struct {
+	id    int16
+	value any
+}
+
+type thriftFields []thriftField
+
+func (s thriftFields) encode() []byte {
+	return s.appendTo(nil)
+}
+
+func (s thriftFields) appendTo(buf []byte) []byte {
+	last := int16(0)
+	for _, f := range s {
+		typ := thriftTypeOf(f.value)
+		if b, ok := f.value.(bool); ok && !b {
+			typ = thriftFalse
+		}
+		if delta := f.id - last; delta > 0 && delta <= 15 {
+			buf = append(buf, byte(delta)<<4|typ)
+		} else {
+			buf = append(buf, typ)
+			buf = binary.AppendUvarint(buf, zigzag(int64(f.id)))
+		}
+		last = f.id
+		buf = appendThriftValue(buf, f.value)
+	}
+	return append(buf, thriftStop)
+}
+
+func thriftTypeOf(v any) byte {
+	switch v.(type) {
+	case bool:
+		return thriftTrue
+	case int8:
+		return thriftByte
+	case int32:
+		return thriftI32
+	case int64:
+		return thriftI64
+	case float64:
+		return thriftDouble
+	case string, []byte:
+		return thriftBinary
+	case thriftFields:
+		return thriftStruct
+	default:
+		return thriftList
+	}
+}
+
+func appendThriftValue(buf []byte, v any) []byte {
+	switch v := v.(type) {
+	case bool:
+		// encoded in the field header
+	case int8:
+		buf = append(buf, byte(v))
+	case int32:
+		buf = binary.AppendUvarint(buf, zigzag(int64(v)))
+	case int64:
+		buf = binary.AppendUvarint(buf, zigzag(v))
+	case float64:
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+	case string:
+		buf = binary.AppendUvarint(buf, uint64(len(v)))
+		buf = append(buf, v...)
+	case []byte:
+		buf = binary.AppendUvarint(buf, uint64(len(v)))
+		buf = append(buf, v...)
+	case thriftFields:
+		buf = v.appendTo(buf)
+	case []int32:
+		buf = appendThriftListHeader(buf, len(v), thriftI32)
+		for _, x := range v {
+			buf = appendThriftValue(buf, x)
+		}
+	case []string:
+		buf = appendThriftListHeader(buf, len(v), thriftBinary)
+		for _, x := range v {
+			buf = appendThriftValue(buf, x)
+		}
+	case []thriftFields:
+		buf = appendThriftListHeader(buf, len(v), thriftStruct)
+		for _, x := range v {
+			buf = x.appendTo(buf)
+		}
+	}
+	return buf
+}
+
+func appendThriftListHeader(buf []byte, n int, elem byte) []byte {
+	if n < 15 {
+		return append(buf, byte(n)<<4|elem)
+	}
+	return binary.AppendUvarint(append(buf, 0xf0|elem), uint64(n))
+}
+
+func zigzag(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+// thriftReader decodes compact protocol values. Malformed input panics with
+// errThrift.
+type thriftReader struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+// thriftValues is a decoded struct. Integers are int64, binaries []byte,
+// structs thriftValues and lists []any.
+type thriftValues map[int16]any
+
+func readThriftStruct(buf []byte) (thriftValues, int) {
+	r := &thriftReader{buf: buf}
+	return r.readStruct(), r.pos
+}
+
+func (r *thriftReader) readByte() byte {
+	if r.pos >= len(r.buf) {
+		panic(errThrift)
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		panic(errThrift)
+	}
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *thriftReader) bytes(n int) []byte {
+	if n < 0 || n > len(r.buf)-r.pos {
+		panic(errThrift)
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *thriftReader) readStruct() thriftValues {
+	r.depth++
+	if r.depth > thriftMaxDepth {
+		panic(errThrift)
+	}
+	values := make(thriftValues)
+	id := int16(0)
+	for {
+		header := r.readByte()
+		typ := header & 0x0f
+		if typ == thriftStop {
+			break
+		}
+		if delta := header >> 4; delta != 0 {
+			id += int16(delta)
+		} else {
+			id = int16(r.varint())
+		}
+		switch typ {
+		case thriftTrue:
+			values[id] = true
+		case thriftFalse:
+			values[id] = false
+		default:
+			values[id] = r.readValue(typ)
+		}
+	}
+	r.depth--
+	return values
+}
+
+func (r *thriftReader) readValue(typ byte) any {
+	switch typ {
+	case thriftTrue, thriftFalse:
+		// only reached for list elements, which are one byte each
+		return r.readByte() == thriftTrue
+	case thriftByte:
+		return int64(int8(r.readByte()))
+	case thriftI16, thriftI32, thriftI64:
+		return r.varint()
+	case thriftDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(r.bytes(8)))
+	case thriftBinary:
+		return r.bytes(int(r.uvarint()))
+	case thriftList, thriftSet:
+		r.depth++
+		if r.depth > thriftMaxDepth {
+			panic(errThrift)
+		}
+		defer func() { r.depth-- }()
+		header := r.readByte()
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		if n > len(r.buf)-r.pos {
+			panic(errThrift)
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.readValue(header & 0x0f)
+		}
+		return list
+	case thriftMap:
+		n := int(r.uvarint())
+		if n == 0 {
+			return nil
+		}
+		if n > len(r.buf)-r.pos {
+			panic(errThrift)
+		}
+		kinds := r.readByte()
+		for i := 0; i < n; i++ {
+			r.readValue(kinds >> 4)
+			r.readValue(kinds & 0x0f)
+		}
+		return nil
+	case thriftStruct:
+		return r.readStruct()
+	}
+	panic(errThrift)
+}
+
+func (v thriftValues) int(id int16) int64 {
+	n, _ := v[id].(int64)
+	return n
+}
+
+func (v thriftValues) has(id int16) bool {
+	_, ok := v[id]
+	return ok
+}
+
+func (v thriftValues) str(id int16) string {
+	b, _ := v[id].([]byte)
+	return string(b)
+}
+
+func (v thriftValues) strct(id int16) thriftValues {
+	s, _ := v[id].(thriftValues)
+	return s
+}
+
+func (v thriftValues) list(id int16) []any {
+	l, _ := v[id].([]any)
+	return l
+}
@@ -0,0 +1,285 @@
+package plygo
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Window computes values over ordered partitions of a pipeline, like SQL
+// window functions. Rows keep their original order in the result.
+type Window[T any] struct {
+	pipeline  *Pipeline[T]
+	partition []string
+	sorts     []sortField
+}
+
+// WindowFunc is a computation for Window.Compute. Each one produces a new
+// column, named after the function unless renamed with As.
+type WindowFunc struct {
+	name    string
+	compute func(rows []any) []any
+}
+
+func (p *Pipeline[T]) Window() *Window[T] {
+	return &Window[T]{pipeline: p}
+}
+
+func (w *Window[T]) PartitionBy(fields ...string) *Window[T] {
+	w.partition = fields
+	return w
+}
+
+func (w *Window[T]) OrderBy(field string) *Window[T] {
+	w.sorts = []sortField{{field: field, desc: false}}
+	return w
+}
+
+func (w *Window[T]) ThenBy(field string) *Window[T] {
+	w.sorts = append(w.sorts, sortField{field: field, desc: false})
+	return w
+}
+
+func (w *Window[T]) Desc() *Window[T] {
+	if len(w.sorts) > 0 {
+		w.sorts[len(w.sorts)-1].desc = true
+	}
+	return w
+}
+
+func (w *Window[T]) Asc() *Window[T] {
+	if len(w.sorts) > 0 {
+		w.sorts[len(w.sorts)-1].desc = false
+	}
+	return w
+}
+
+// Compute returns a map pipeline with every field of the source rows plus
+// one column per function.
+func (w *Window[T]) Compute(fns ...WindowFunc) *Pipeline[map[string]any] {
+	data := w.pipeline.data
+	fields := w.pipeline.FieldNames()
+
+	result := make([]map[string]any, len(data))
+	for i, item := range data {
+		row := make(map[string]any, len(fields)+len(fns))
+		for _, field := range fields {
+			row[field] = getFieldValue(item, field)
+		}
+		result[i] = row
+	}
+
+	for _, positions := range w.partitions() {
+		sort.SliceStable(positions, func(a, b int) bool {
+			return lessBy(w.sorts, data[positions[a]], data[positions[b]])
+		})
+
+		rows := make([]any, len(positions))
+		for k, pos := range positions {
+			rows[k] = data[pos]
+		}
+
+		for _, fn := range fns {
+			values := fn.compute(rows)
+			for k, pos := range positions {
+				result[pos][fn.name] = values[k]
+			}
+		}
+	}
+
+	columns := append([]string(nil), fields...)
+	for _, fn := range fns {
+		columns = append(columns, fn.name)
+	}
+
+	resultIdx := make([]int, len(data))
+	copy(resultIdx, w.pipeline.originalIndex)
+
+	return &Pipeline[map[string]any]{data: result, originalIndex: resultIdx, columns: columns}
+}
+
+func (w *Window[T]) partitions() [][]int {
+	if len(w.partition) == 0 {
+		all := make([]int, len(w.pipeline.data))
+		for i := range all {
+			all[i] = i
+		}
+		return [][]int{all}
+	}
+
+	grouping := &Grouping[T]{pipeline: w.pipeline, fields: w.partition}
+	keys, members := grouping.partition()
+
+	result := make([][]int, len(keys))
+	for i, key := range keys {
+		result[i] = members[key]
+	}
+	return result
+}
+
+// As renames the column produced by the function.
+func (f WindowFunc) As(name string) WindowFunc {
+	f.name = name
+	return f
+}
+
+// RowNumber numbers rows 1, 2, 3... within each partition.
+func RowNumber() WindowFunc {
+	return WindowFunc{name: "RowNumber", compute: func(rows []any) []any {
+		result := make([]any, len(rows))
+		for i := range rows {
+			result[i] = i + 1
+		}
+		return result
+	}}
+}
+
+// Rank ranks rows within each partition by field, highest first. Ties
+// share a rank and leave a gap after them, like SQL RANK.
+func Rank(field string) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("Rank(%s)", field), compute: func(rows []any) []any {
+		return rankRows(rows, field, false)
+	}}
+}
+
+// DenseRank is like Rank but without gaps after ties.
+func DenseRank(field string) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("DenseRank(%s)", field), compute: func(rows []any) []any {
+		return rankRows(rows, field, true)
+	}}
+}
+
+// Lag returns the value of field n rows earlier in the partition, or nil.
+func Lag(field string, n int) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("Lag(%s, %d)", field, n), compute: func(rows []any) []any {
+		return shiftRows(rows, field, -n)
+	}}
+}
+
+// Lead returns the value of field n rows later in the partition, or nil.
+func Lead(field string, n int) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("Lead(%s, %d)", field, n), compute: func(rows []any) []any {
+		return shiftRows(rows, field, n)
+	}}
+}
+
+// CumSum is the running total of field within the partition.
+func CumSum(field string) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("CumSum(%s)", field), compute: func(rows []any) []any {
+		result := make([]any, len(rows))
+		total := 0.0
+		for i, row := range rows {
+			total += toFloat64(getFieldValue(row, field))
+			result[i] = total
+		}
+		return result
+	}}
+}
+
+// CumMax is the running maximum of field within the partition.
+func CumMax(field string) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("CumMax(%s)", field), compute: func(rows []any) []any {
+		return cumulative(rows, field, 1)
+	}}
+}
+
+// CumMin is the running minimum of field within the partition.
+func CumMin(field string) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("CumMin(%s)", field), compute: func(rows []any) []any {
+		return cumulative(rows, field, -1)
+	}}
+}
+
+// MovingSum sums field over the current row and up to n-1 preceding rows.
+// Nil values are skipped; a window with no values gives nil.
+func MovingSum(field string, n int) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("MovingSum(%s, %d)", field, n), compute: func(rows []any) []any {
+		return movingRows(rows, field, n, false)
+	}}
+}
+
+// MovingAvg averages field over the current row and up to n-1 preceding
+// rows; the first rows of a partition average what is available, and nil
+// values are left out of the average.
+func MovingAvg(field string, n int) WindowFunc {
+	return WindowFunc{name: fmt.Sprintf("MovingAvg(%s, %d)", field, n), compute: func(rows []any) []any {
+		return movingRows(rows, field, n, true)
+	}}
+}
+
+func rankRows(rows []any, field string, dense bool) []any {
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return compareValues(getFieldValue(rows[order[a]], field), getFieldValue(rows[order[b]], field)) > 0
+	})
+
+	result := make([]any, len(rows))
+	rank := 0
+	for k, pos := range order {
+		if k == 0 || compareValues(getFieldValue(rows[pos], field), getFieldValue(rows[order[k-1]], field)) != 0 {
+			if dense {
+				rank++
+			} else {
+				rank = k + 1
+			}
+		}
+		result[pos] = rank
+	}
+	return result
+}
+
+func shiftRows(rows []any, field string, offset int) []any {
+	result := make([]any, len(rows))
+	for i := range rows {
+		j := i + offset
+		if j >= 0 && j < len(rows) {
+			result[i] = getFieldValue(rows[j], field)
+		}
+	}
+	return result
+}
+
+func cumulative(rows []any, field string, direction int) []any {
+	result := make([]any, len(rows))
+	var current any
+	for i, row := range rows {
+		val := getFieldValue(row, field)
+		if i == 0 || compareValues(val, current)*direction > 0 {
+			current = val
+		}
+		result[i] = current
+	}
+	return result
+}
+
+// movingRows sums each window from scratch, so an Inf or NaN only affects
+// the windows that contain it. Nil values are skipped: they add nothing to
+// the sum and are left out of the average, and a window holding only nils
+// yields nil.
+func movingRows(rows []any, field string, n int, average bool) []any {
+	result := make([]any, len(rows))
+	if n <= 0 {
+		return result
+	}
+
+	for i := range rows {
+		total, count := 0.0, 0
+		for _, row := range rows[max(0, i-n+1) : i+1] {
+			if val := derefValue(getFieldValue(row, field)); val != nil {
+				total += toFloat64(val)
+				count++
+			}
+		}
+
+		switch {
+		case count == 0:
+		case average:
+			result[i] = total / float64(count)
+		default:
+			result[i] = total
+		}
+	}
+	return result
+}
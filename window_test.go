@@ -0,0 +1,161 @@
+package plygo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// windowPeople mirrors a small ledger: City is the account, Age orders the
+// rows within it and Salary is the amount.
+func windowPeople() []Person {
+	return []Person{
+		{"Alice", 33, "NYC", 30000, true},
+		{"Bob", 31, "LA", 100000, true},
+		{"Charlie", 31, "NYC", 10000, false},
+		{"Diana", 32, "LA", 40000, true},
+		{"Eve", 32, "NYC", 20000, true},
+	}
+}
+
+func column(rows []map[string]any, name string) []any {
+	result := make([]any, len(rows))
+	for i, row := range rows {
+		result[i] = row[name]
+	}
+	return result
+}
+
+func TestWindow_CumSumAndLag(t *testing.T) {
+	result := From(windowPeople()).
+		Window().
+		PartitionBy("City").
+		OrderBy("Age").
+		Compute(CumSum("Salary").As("Balance"), Lag("Salary", 1), RowNumber())
+
+	rows := result.Collect()
+
+	if !reflect.DeepEqual(column(rows, "Balance"), []any{60000.0, 100000.0, 10000.0, 140000.0, 30000.0}) {
+		t.Errorf("Unexpected balances: %v", column(rows, "Balance"))
+	}
+	if !reflect.DeepEqual(column(rows, "Lag(Salary, 1)"), []any{20000.0, nil, nil, 100000.0, 10000.0}) {
+		t.Errorf("Unexpected lags: %v", column(rows, "Lag(Salary, 1)"))
+	}
+	if !reflect.DeepEqual(column(rows, "RowNumber"), []any{3, 1, 1, 2, 2}) {
+		t.Errorf("Unexpected row numbers: %v", column(rows, "RowNumber"))
+	}
+
+	if !reflect.DeepEqual(result.Which(), []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected original order, got %v", result.Which())
+	}
+	expectedCols := []string{"Name", "Age", "City", "Salary", "Active", "Balance", "Lag(Salary, 1)", "RowNumber"}
+	if !reflect.DeepEqual(result.FieldNames(), expectedCols) {
+		t.Errorf("Expected columns %v, got %v", expectedCols, result.FieldNames())
+	}
+}
+
+func TestWindow_Lead(t *testing.T) {
+	rows := From(windowPeople()).
+		Window().
+		PartitionBy("City").
+		OrderBy("Age").
+		Compute(Lead("Salary", 1).As("Next")).
+		Collect()
+
+	if !reflect.DeepEqual(column(rows, "Next"), []any{nil, 40000.0, 20000.0, nil, 30000.0}) {
+		t.Errorf("Unexpected leads: %v", column(rows, "Next"))
+	}
+}
+
+func TestWindow_Rank(t *testing.T) {
+	scores := []Person{
+		{"Alice", 30, "NYC", 90000, true},
+		{"Bob", 25, "NYC", 95000, true},
+		{"Charlie", 35, "NYC", 90000, false},
+		{"Diana", 28, "NYC", 80000, true},
+	}
+
+	rows := From(scores).Window().Compute(Rank("Salary"), DenseRank("Salary")).Collect()
+
+	if !reflect.DeepEqual(column(rows, "Rank(Salary)"), []any{2, 1, 2, 4}) {
+		t.Errorf("Unexpected ranks: %v", column(rows, "Rank(Salary)"))
+	}
+	if !reflect.DeepEqual(column(rows, "DenseRank(Salary)"), []any{2, 1, 2, 3}) {
+		t.Errorf("Unexpected dense ranks: %v", column(rows, "DenseRank(Salary)"))
+	}
+}
+
+func TestWindow_Moving(t *testing.T) {
+	prices := []map[string]any{
+		{"Day": 1, "Price": 10},
+		{"Day": 2, "Price": 20},
+		{"Day": 3, "Price": 30},
+		{"Day": 4, "Price": 40},
+	}
+
+	rows := From(prices).
+		Window().
+		OrderBy("Day").Desc().
+		Compute(MovingAvg("Price", 2), MovingSum("Price", 3), CumMax("Price"), CumMin("Price")).
+		Collect()
+
+	if !reflect.DeepEqual(column(rows, "MovingAvg(Price, 2)"), []any{15.0, 25.0, 35.0, 40.0}) {
+		t.Errorf("Unexpected moving averages: %v", column(rows, "MovingAvg(Price, 2)"))
+	}
+	if !reflect.DeepEqual(column(rows, "MovingSum(Price, 3)"), []any{60.0, 90.0, 70.0, 40.0}) {
+		t.Errorf("Unexpected moving sums: %v", column(rows, "MovingSum(Price, 3)"))
+	}
+	if !reflect.DeepEqual(column(rows, "CumMax(Price)"), []any{40, 40, 40, 40}) {
+		t.Errorf("Unexpected running max: %v", column(rows, "CumMax(Price)"))
+	}
+	if !reflect.DeepEqual(column(rows, "CumMin(Price)"), []any{10, 20, 30, 40}) {
+		t.Errorf("Unexpected running min: %v", column(rows, "CumMin(Price)"))
+	}
+}
+
+func TestWindow_EmptyAndNaN(t *testing.T) {
+	empty := From([]Person{}).Window().PartitionBy("City").OrderBy("Age").Compute(CumSum("Salary"), RowNumber())
+	if empty.Count() != 0 {
+		t.Errorf("Expected no rows, got %d", empty.Count())
+	}
+
+	people := windowPeople()
+	people[2].Salary = math.NaN()
+	rows := From(people).Window().Compute(Rank("Salary"), CumSum("Salary")).Collect()
+	if len(rows) != 5 {
+		t.Fatalf("Expected 5 rows, got %d", len(rows))
+	}
+	// NaN sorts below every number, so it ranks last in descending order
+	if rank := column(rows, "Rank(Salary)"); !reflect.DeepEqual(rank, []any{3, 1, 5, 2, 4}) {
+		t.Errorf("Unexpected ranks with a NaN salary: %v", rank)
+	}
+
+	// a non-finite value only affects the windows that contain it
+	people = windowPeople()[:4]
+	for i, salary := range []float64{math.Inf(1), 1, 2, 3} {
+		people[i].Salary = salary
+	}
+	rows = From(people).Window().Compute(MovingSum("Salary", 2)).Collect()
+	if sums := column(rows, "MovingSum(Salary, 2)"); !reflect.DeepEqual(sums, []any{math.Inf(1), math.Inf(1), 3.0, 5.0}) {
+		t.Errorf("Unexpected moving sums after Inf: %v", sums)
+	}
+
+	people[0].Salary, people[1].Salary = 1, math.NaN()
+	rows = From(people).Window().Compute(MovingAvg("Salary", 2)).Collect()
+	avgs := column(rows, "MovingAvg(Salary, 2)")
+	if avgs[0] != 1.0 || !math.IsNaN(avgs[1].(float64)) || !math.IsNaN(avgs[2].(float64)) || avgs[3] != 2.5 {
+		t.Errorf("Unexpected moving averages around NaN: %v", avgs)
+	}
+}
+
+func TestWindow_MovingNil(t *testing.T) {
+	records := []map[string]any{{"v": 1}, {"v": nil}, {"v": nil}, {"v": 4}}
+	rows := FromRecords(records).Window().Compute(MovingSum("v", 2), MovingAvg("v", 3)).Collect()
+
+	if sums := column(rows, "MovingSum(v, 2)"); !reflect.DeepEqual(sums, []any{1.0, 1.0, nil, 4.0}) {
+		t.Errorf("Expected nils to be skipped, got %v", sums)
+	}
+	if avgs := column(rows, "MovingAvg(v, 3)"); !reflect.DeepEqual(avgs, []any{1.0, 1.0, 1.0, 4.0}) {
+		t.Errorf("Expected nils to be left out of the average, got %v", avgs)
+	}
+}
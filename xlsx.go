@@ -0,0 +1,427 @@
+package plygo
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// XLSXConfig holds the settings of WriteXLSX: the Show settings that apply
+// to a workbook, plus the sheet name.
+type XLSXConfig struct {
+	show      *ShowConfig
+	sheetName string
+}
+
+// XLSXOption configures WriteXLSX. WithSheetName returns one, and every
+// ShowOption is one too, so Show options can be passed alongside it.
+type XLSXOption interface {
+	applyXLSX(*XLSXConfig)
+}
+
+func (o ShowOption) applyXLSX(c *XLSXConfig) { o(c.show) }
+
+type xlsxOptionFunc func(*XLSXConfig)
+
+func (f xlsxOptionFunc) applyXLSX(c *XLSXConfig) { f(c) }
+
+// WithSheetName sets the worksheet name used by WriteXLSX (default
+// "Sheet1"). Grouping.WriteXLSX names each sheet after its group instead.
+func WithSheetName(name string) XLSXOption {
+	return xlsxOptionFunc(func(c *XLSXConfig) { c.sheetName = name })
+}
+
+// xlsxSheet is one worksheet: a header row and raw cell values.
+type xlsxSheet struct {
+	name    string
+	headers []string
+	rows    [][]any
+}
+
+// WriteXLSX writes the pipeline as an Excel workbook with a single sheet.
+// Columns follow WriteCSV. Numbers, booleans and times are stored as typed
+// cells; everything else is written as text. Besides WithSheetName, the
+// Show options WithTitle (a title row above the header), WithMaxColWidth,
+// WithFloatPrecision (the number format of float columns), WithRowNumbers
+// and WithOriginalIndices apply; every row is written regardless of
+// WithMaxRows.
+func (p *Pipeline[T]) WriteXLSX(w io.Writer, options ...XLSXOption) error {
+	config := xlsxConfig(options)
+	return writeXLSX(w, []xlsxSheet{p.xlsxSheet(config.sheetName, config.show)}, config.show)
+}
+
+// WriteXLSX writes the selected fields in selection order.
+func (s *Selection[T]) WriteXLSX(w io.Writer, options ...XLSXOption) error {
+	config := xlsxConfig(options)
+	rows := s.execute()
+
+	sheet := xlsxSheet{name: config.sheetName, headers: s.fields, rows: make([][]any, len(rows))}
+	for i, row := range rows {
+		sheet.rows[i] = make([]any, len(s.fields))
+		for j, field := range s.fields {
+			sheet.rows[i][j] = row[field]
+		}
+	}
+	addXLSXIndex(&sheet, s.pipeline.originalIndex, config.show)
+	return writeXLSX(w, []xlsxSheet{sheet}, config.show)
+}
+
+// WriteXLSX writes one sheet per group, in group order, named after the
+// group key.
+func (g *Grouping[T]) WriteXLSX(w io.Writer, options ...XLSXOption) error {
+	config := xlsxConfig(options)
+	groups := g.Groups()
+
+	sheets := make([]xlsxSheet, len(groups))
+	for i, group := range groups {
+		key := g.jsonKey(group)
+		name := fmt.Sprint(key)
+		if key, ok := key.(orderedObject); ok {
+			parts := make([]string, len(key.keys))
+			for j, k := range key.keys {
+				parts[j] = fmt.Sprint(key.values[k])
+			}
+			name = strings.Join(parts, ", ")
+		}
+		sheets[i] = group.Rows.xlsxSheet(name, config.show)
+	}
+	if len(sheets) == 0 {
+		sheets = append(sheets, g.pipeline.xlsxSheet(config.sheetName, config.show))
+	}
+	return writeXLSX(w, sheets, config.show)
+}
+
+func xlsxConfig(options []XLSXOption) *XLSXConfig {
+	config := &XLSXConfig{show: defaultShowConfig(), sheetName: "Sheet1"}
+	for _, opt := range options {
+		opt.applyXLSX(config)
+	}
+	return config
+}
+
+func (p *Pipeline[T]) xlsxSheet(name string, config *ShowConfig) xlsxSheet {
+	headers, fields := p.exportColumns()
+	sheet := xlsxSheet{name: name, headers: headers, rows: make([][]any, len(p.data))}
+	for i, item := range p.data {
+		sheet.rows[i] = make([]any, len(fields))
+		for j, field := range fields {
+			sheet.rows[i][j] = getFieldValue(item, field)
+		}
+	}
+	addXLSXIndex(&sheet, p.originalIndex, config)
+	return sheet
+}
+
+// addXLSXIndex prepends the "#" column used by Show.
+func addXLSXIndex(sheet *xlsxSheet, originalIndex []int, config *ShowConfig) {
+	if !config.showRowNumbers && !config.showOriginalIdx {
+		return
+	}
+	sheet.headers = append([]string{"#"}, sheet.headers...)
+	for i, row := range sheet.rows {
+		idx := i + 1
+		if !config.showRowNumbers && i < len(originalIndex) {
+			idx = originalIndex[i]
+		}
+		sheet.rows[i] = append([]any{idx}, row...)
+	}
+}
+
+// Cell styles defined in xlsxStyles.
+const (
+	xlsxStyleHeader = 1
+	xlsxStyleTitle  = 2
+	xlsxStyleTime   = 3
+	xlsxStyleFloat  = 4
+)
+
+const xlsxTimeLayout = "2006-01-02 15:04:05"
+
+func writeXLSX(w io.Writer, sheets []xlsxSheet, config *ShowConfig) error {
+	zw := zip.NewWriter(w)
+	names := xlsxSheetNames(sheets)
+
+	files := []struct {
+		name, content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes(len(sheets))},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(names)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(sheets))},
+		{"xl/styles.xml", xlsxStyles(config.floatPrecision)},
+	}
+	for i, sheet := range sheets {
+		files = append(files, struct{ name, content string }{
+			fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxWorksheet(sheet, config),
+		})
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// xlsxSheetNames makes sheet names valid for Excel: at most 31 characters,
+// none of []:*?/\ and unique ignoring case.
+func xlsxSheetNames(sheets []xlsxSheet) []string {
+	names := make([]string, len(sheets))
+	seen := make(map[string]bool)
+	for i, sheet := range sheets {
+		base := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return '_'
+			}
+			return r
+		}, sheet.name)
+		base = strings.Trim(base, "'")
+		if base == "" {
+			base = fmt.Sprintf("Sheet%d", i+1)
+		}
+
+		name := truncateRunes(base, 31)
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			name = truncateRunes(base, 31-len(suffix)) + suffix
+		}
+		seen[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+func xlsxWorksheet(sheet xlsxSheet, config *ShowConfig) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	headerRow := 1
+	if config.title != "" {
+		headerRow = 2
+	}
+	fmt.Fprintf(&b, `<sheetViews><sheetView workbookViewId="0"><pane ySplit="%d" topLeftCell="A%d" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`,
+		headerRow, headerRow+1)
+
+	texts := make([][]string, len(sheet.rows))
+	for i, row := range sheet.rows {
+		texts[i] = make([]string, len(row))
+		for j, v := range row {
+			texts[i][j] = xlsxText(v, config)
+		}
+	}
+	if len(sheet.headers) > 0 {
+		b.WriteString("<cols>")
+		for i, width := range calculateColumnWidths(sheet.headers, texts, config.maxColWidth) {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width+2)
+		}
+		b.WriteString("</cols>")
+	}
+
+	b.WriteString("<sheetData>")
+	if config.title != "" {
+		b.WriteString(`<row r="1">`)
+		writeXLSXString(&b, "A1", config.title, xlsxStyleTitle)
+		b.WriteString("</row>")
+	}
+	fmt.Fprintf(&b, `<row r="%d">`, headerRow)
+	for j, header := range sheet.headers {
+		writeXLSXString(&b, xlsxCellRef(j, headerRow), header, xlsxStyleHeader)
+	}
+	b.WriteString("</row>")
+
+	for i, row := range sheet.rows {
+		r := headerRow + 1 + i
+		fmt.Fprintf(&b, `<row r="%d">`, r)
+		for j, v := range row {
+			writeXLSXCell(&b, xlsxCellRef(j, r), v, config)
+		}
+		b.WriteString("</row>")
+	}
+	b.WriteString("</sheetData>")
+
+	if config.title != "" && len(sheet.headers) > 1 {
+		fmt.Fprintf(&b, `<mergeCells count="1"><mergeCell ref="A1:%s"/></mergeCells>`, xlsxCellRef(len(sheet.headers)-1, 1))
+	}
+	b.WriteString("</worksheet>")
+	return b.String()
+}
+
+// xlsxText is the displayed text of a cell, used for column widths.
+func xlsxText(v any, config *ShowConfig) string {
+	v = derefValue(v)
+	if v == nil {
+		return ""
+	}
+	if t, ok := v.(time.Time); ok {
+		return t.Format(xlsxTimeLayout)
+	}
+	return formatValue(v, config)
+}
+
+func derefValue(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+// xlsxCellRef returns the A1-style reference of a zero-based column and a
+// one-based row.
+func xlsxCellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+func writeXLSXCell(b *strings.Builder, ref string, v any, config *ShowConfig) {
+	v = derefValue(v)
+	if v == nil {
+		return
+	}
+
+	switch val := v.(type) {
+	case bool:
+		n := 0
+		if val {
+			n = 1
+		}
+		fmt.Fprintf(b, `<c r="%s" t="b"><v>%d</v></c>`, ref, n)
+		return
+	case time.Time:
+		if val.IsZero() {
+			return
+		}
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xlsxStyleTime, strconv.FormatFloat(excelSerial(val), 'f', -1, 64))
+		return
+	case time.Duration:
+		writeXLSXString(b, ref, val.String(), 0)
+		return
+	}
+
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		fmt.Fprintf(b, `<c r="%s"><v>%d</v></c>`, ref, rv.Int())
+	case rv.CanUint():
+		fmt.Fprintf(b, `<c r="%s"><v>%d</v></c>`, ref, rv.Uint())
+	case rv.CanFloat() && !math.IsNaN(rv.Float()) && !math.IsInf(rv.Float(), 0):
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xlsxStyleFloat, strconv.FormatFloat(rv.Float(), 'g', -1, 64))
+	default:
+		writeXLSXString(b, ref, formatCell(v, defaultCSVConfig()), 0)
+	}
+}
+
+func writeXLSXString(b *strings.Builder, ref, s string, style int) {
+	fmt.Fprintf(b, `<c r="%s" t="inlineStr"`, ref)
+	if style != 0 {
+		fmt.Fprintf(b, ` s="%d"`, style)
+	}
+	b.WriteString(`><is><t xml:space="preserve">`)
+	xml.EscapeText(b, []byte(s))
+	b.WriteString("</t></is></c>")
+}
+
+// excelSerial converts a time to Excel's serial date: days since
+// 1899-12-30, read on the wall clock of the time's own location.
+func excelSerial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return float64(wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC))) / float64(24*time.Hour)
+}
+
+func xlsxContentTypes(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func xlsxWorkbook(names []string) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range names {
+		b.WriteString(`<sheet name="`)
+		xml.EscapeText(&b, []byte(name))
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func xlsxWorkbookRels(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// xlsxStyles defines the default style plus the header, title, time and
+// float styles, in that order.
+func xlsxStyles(precision int) string {
+	floatFormat := "0"
+	if precision > 0 {
+		floatFormat += "." + strings.Repeat("0", precision)
+	}
+	return xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy\-mm\-dd\ hh:mm:ss"/>` +
+		`<numFmt numFmtId="165" formatCode="` + floatFormat + `"/></numFmts>` +
+		`<fonts count="3"><font><sz val="11"/><name val="Calibri"/></font>` +
+		`<font><b/><sz val="11"/><name val="Calibri"/></font>` +
+		`<font><b/><sz val="14"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="5"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+		`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+		`</styleSheet>`
+}
@@ -0,0 +1,208 @@
+package plygo
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type XLSXSale struct {
+	Region string
+	Amount float64 `plygo:"amount"`
+	Units  int
+	Paid   bool
+	Date   time.Time
+	Note   *string
+}
+
+func xlsxSales() []XLSXSale {
+	note := "rush <order> & co"
+	day := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	return []XLSXSale{
+		{"North", 1250.5, 3, true, day, &note},
+		{"South", 80, 1, false, day.AddDate(0, 0, 1), nil},
+		{"North", 99.99, 2, true, day.AddDate(0, 0, 2), nil},
+	}
+}
+
+// readXLSX unzips a workbook, checks that every part is well-formed XML and
+// returns the parts by name.
+func readXLSX(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+
+		dec := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", f.Name, err)
+			}
+		}
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	err := From(xlsxSales()).WriteXLSX(&buf, WithTitle("Q1 Sales"), WithSheetName("Sales"), WithOriginalIndices(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parts := readXLSX(t, buf.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Missing part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Sales" sheetId="1" r:id="rId1"/>`) {
+		t.Errorf("Unexpected workbook %s", parts["xl/workbook.xml"])
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	expected := []string{
+		`<c r="A1" t="inlineStr" s="2"><is><t xml:space="preserve">Q1 Sales</t></is></c>`,
+		`<mergeCell ref="A1:G1"/>`,
+		`<c r="C2" t="inlineStr" s="1"><is><t xml:space="preserve">amount</t></is></c>`,
+		`<c r="A3"><v>1</v></c>`,
+		`<c r="C3" s="4"><v>1250.5</v></c>`,
+		`<c r="D3"><v>3</v></c>`,
+		`<c r="E3" t="b"><v>1</v></c>`,
+		`<c r="F3" s="3"><v>45293.5</v></c>`,
+		`rush &lt;order&gt; &amp; co`,
+		`<pane ySplit="2" topLeftCell="A3"`,
+		`<col min="7" max="7" width="19" customWidth="1"/>`,
+		`<col min="6" max="6" width="21" customWidth="1"/>`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(sheet, s) {
+			t.Errorf("Expected sheet to contain %s", s)
+		}
+	}
+	if strings.Contains(sheet, `r="G4"`) {
+		t.Error("Expected nil pointers to be written as empty cells")
+	}
+	if !strings.Contains(parts["xl/styles.xml"], `formatCode="0.00"`) {
+		t.Error("Expected float format from the default precision")
+	}
+}
+
+func TestWriteXLSXSelectionAndGroups(t *testing.T) {
+	var buf bytes.Buffer
+	if err := From(xlsxSales()).Select("Units", "Region").WriteXLSX(&buf, WithFloatPrecision(0)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parts := readXLSX(t, buf.Bytes())
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(sheet, `<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Units</t>`) ||
+		!strings.Contains(sheet, `<c r="B2" t="inlineStr"><is><t xml:space="preserve">North</t>`) {
+		t.Errorf("Unexpected selection sheet %s", sheet)
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Sheet1"`) || !strings.Contains(parts["xl/styles.xml"], `formatCode="0"`) {
+		t.Error("Expected default sheet name and integer float format")
+	}
+
+	buf.Reset()
+	if err := From(xlsxSales()).GroupBy("Region").WriteXLSX(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parts = readXLSX(t, buf.Bytes())
+	workbook := parts["xl/workbook.xml"]
+	if !strings.Contains(workbook, `name="North" sheetId="1"`) || !strings.Contains(workbook, `name="South" sheetId="2"`) {
+		t.Errorf("Expected one sheet per region, got %s", workbook)
+	}
+	if strings.Count(parts["xl/worksheets/sheet1.xml"], "<row ") != 3 {
+		t.Error("Expected header and two rows on the North sheet")
+	}
+}
+
+func TestWriteXLSXOptions(t *testing.T) {
+	options := []XLSXOption{WithTitle("Report"), WithSheetName("Data"), WithOriginalIndices(true)}
+
+	var buf bytes.Buffer
+	if err := From(xlsxSales()).WriteXLSX(&buf, options...); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parts := readXLSX(t, buf.Bytes())
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Data"`) {
+		t.Errorf("Expected sheet name Data, got %s", parts["xl/workbook.xml"])
+	}
+	if sheet := parts["xl/worksheets/sheet1.xml"]; !strings.Contains(sheet, ">Report<") || !strings.Contains(sheet, `<c r="A2" t="inlineStr" s="1"><is><t xml:space="preserve">#</t>`) {
+		t.Errorf("Expected Show options to apply, got %s", sheet)
+	}
+
+	config := xlsxConfig(options)
+	if config.sheetName != "Data" || config.show.title != "Report" {
+		t.Errorf("Unexpected config %+v", config)
+	}
+	if xlsxConfig(nil).sheetName != "Sheet1" {
+		t.Error("Expected default sheet name Sheet1")
+	}
+}
+
+func TestWriteXLSXEmptyAndNaN(t *testing.T) {
+	var buf bytes.Buffer
+	if err := From([]Person{}).WriteXLSX(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sheet := readXLSX(t, buf.Bytes())["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(sheet, `<c r="E1" t="inlineStr" s="1"><is><t xml:space="preserve">Active</t></is></c></row></sheetData>`) {
+		t.Errorf("Expected only the header row, got %s", sheet)
+	}
+
+	buf.Reset()
+	people := []Person{{"Alice", 30, "NYC", math.NaN(), true}, {"Bob", 25, "LA", math.Inf(1), true}}
+	if err := From(people).WriteXLSX(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sheet = readXLSX(t, buf.Bytes())["xl/worksheets/sheet1.xml"]
+	for _, s := range []string{
+		`<c r="D2" t="inlineStr"><is><t xml:space="preserve">NaN</t></is></c>`,
+		`<c r="D3" t="inlineStr"><is><t xml:space="preserve">+Inf</t></is></c>`,
+	} {
+		if !strings.Contains(sheet, s) {
+			t.Errorf("Expected non-finite floats as text, missing %s", s)
+		}
+	}
+}
+
+func TestXLSXHelpers(t *testing.T) {
+	refs := map[[2]int]string{{0, 1}: "A1", {25, 2}: "Z2", {26, 3}: "AA3", {701, 4}: "ZZ4", {702, 5}: "AAA5"}
+	for in, want := range refs {
+		if got := xlsxCellRef(in[0], in[1]); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+
+	names := xlsxSheetNames([]xlsxSheet{
+		{name: "a/b"}, {name: "A_B"}, {name: ""}, {name: strings.Repeat("x", 40)}, {name: strings.Repeat("x", 40)},
+	})
+	expected := []string{"a_b", "A_B (2)", "Sheet3", strings.Repeat("x", 31), strings.Repeat("x", 27) + " (2)"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Expected sheet name %q, got %q", expected[i], names[i])
+		}
+	}
+
+	if got := excelSerial(time.Date(1900, 3, 1, 6, 0, 0, 0, time.UTC)); got != 61.25 {
+		t.Errorf("Expected serial 61.25, got %v", got)
+	}
+}